@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bit-wordy/src/cached"
+	"bit-wordy/src/games"
+	"bit-wordy/src/primitives"
+	"fmt"
+	"strings"
+)
+
+// Analyse is the subcommand that grades each guess of finished games, such as a replay saved
+// with --save or --jsonl. A game played by hand can be given with --guesses, a comma separated
+// list of words played against the --answer, e.g. --answer light --guesses tares,fight,light,
+// the --answer must name the word, or the file or daily puzzle it comes from
+type Analyse struct {
+	Replays []string `arg:"positional"`
+	Guesses string   `arg:"--guesses"`
+}
+
+// Run is the implementation of Analyse
+func (a Analyse) Run(p *cached.Patterns) (err error) {
+	if p == nil {
+		if p, err = loadPatterns(); err != nil {
+			return err
+		}
+	}
+
+	played := []*games.Game{}
+	if a.Guesses != "" {
+		// a random answer would grade the guesses against a word they weren't played against
+		switch mode, _, _ := strings.Cut(args.Answer, ":"); mode {
+		case "", "random", "sequential", "shuffled":
+			return fmt.Errorf("--guesses needs the --answer they were played against")
+		}
+		source, err := answerSource(false)
+		if err != nil {
+			return err
+		}
+		g := newGame(source.Next())
+		for _, guess := range strings.Split(a.Guesses, ",") {
+			if g.IsWon() || g.IsLost() {
+				return fmt.Errorf("the game was over before %s", guess)
+			}
+			g.Guess(primitives.MakeWord(strings.TrimSpace(guess)))
+		}
+		played = append(played, g)
+	}
+	for _, path := range a.Replays {
+		replays, err := readReplayFile(path)
+		if err != nil {
+			return err
+		}
+		for _, replay := range replays {
+			played = append(played, replay.Game)
+		}
+	}
+	if len(played) == 0 {
+		return fmt.Errorf("no games to analyse, give replay files or --guesses")
+	}
+
+	for _, g := range played {
+		analysis, err := p.Analyse(g)
+		if err != nil {
+			return fmt.Errorf("analysing the game of %s: %w", g.Answer, err)
+		}
+		fmt.Print(analysis)
+	}
+	return nil
+}
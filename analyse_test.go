@@ -0,0 +1,27 @@
+package main
+
+import (
+	"bit-wordy/src/cached"
+	"strings"
+	"testing"
+)
+
+func TestAnalyse_Guesses(t *testing.T) {
+	path := wordsFile(t, "tares", "light", "might", "night", "sight", "fight", "crane")
+	for _, cmdline := range [][]string{
+		{"analyse", "--guesses", "tares,light"},
+		{"--answer", "random", "analyse", "--guesses", "tares,light"},
+		{"--answer", "shuffled", "analyse", "--guesses", "tares,light"},
+	} {
+		parseArgs(t, append([]string{"--words", path}, cmdline...)...)
+		if err := args.Analyse.Run(cached.BuildPatternsWith(words, scoring)); err == nil {
+			t.Errorf("%v graded the guesses against an answer that wasn't given", cmdline)
+		}
+	}
+
+	parseArgs(t, "--words", path, "--answer", "light", "analyse", "--guesses", "tares,night,light")
+	out := captureStdout(t, func() error { return args.Analyse.Run(cached.BuildPatternsWith(words, scoring)) })
+	if !strings.Contains(out, "light") || !strings.Contains(out, "night") {
+		t.Errorf("analysed the game of light as:\n%s", out)
+	}
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"bit-wordy/src/cached"
+	"bit-wordy/src/primitives"
+	"fmt"
+	"time"
+)
+
+// Bench is the subcommand that measures the selected strategy, either over every word in
+// the dictionary or over a number of answers chosen by --answer. It fits the curve used by
+// the expected score strategy from the games it plays, --save-curve writes the fit out
+// for use with --curve. --jsonl writes a replay of every game, see Replay. --baseline plays
+// the same answers with the entropy strategy too and lists the losses each avoided.
+type Bench struct {
+	Games       int    `arg:"positional"`
+	SamplePrior bool   `arg:"--sample-prior"`
+	SaveCurve   string `arg:"--save-curve"`
+	JSONL       string `arg:"--jsonl"`
+	Baseline    bool   `arg:"--baseline"`
+}
+
+// answers returns the answers to benchmark against
+func (b Bench) answers() (primitives.Dictionary, error) {
+	if b.Games <= 0 {
+		if len(words) == 0 {
+			return nil, fmt.Errorf("there are no words to play")
+		}
+		return words, nil
+	}
+
+	source, err := answerSource(b.SamplePrior)
+	if err != nil {
+		return nil, err
+	}
+	answers := make(primitives.Dictionary, b.Games)
+	for i := range answers {
+		answers[i] = source.Next()
+	}
+
+	return answers, nil
+}
+
+// benchResult is the outcome of solving a set of answers
+type benchResult struct {
+	games, lost, guesses int
+	lostAnswers          primitives.Dictionary
+	distribution         map[int]int
+	samples              []cached.CurveSample
+	elapsed              time.Duration
+}
+
+// runBench solves every answer with the solver, logging each game
+func runBench(solver *cached.FastSolver, answers primitives.Dictionary, replays *replayLog) (benchResult, error) {
+	r := benchResult{games: len(answers), distribution: map[int]int{}}
+	start := time.Now()
+	for _, answer := range answers {
+		solver.Reset()
+		game, playDuration, err := solver.Solve(newGame(answer))
+		if err != nil {
+			return r, err
+		}
+		if err := replays.Write(solver.Replay(game, playDuration)); err != nil {
+			return r, err
+		}
+		r.samples = append(r.samples, solver.CurveSamples(game)...)
+		if game.IsLost() {
+			r.lost++
+			r.lostAnswers = append(r.lostAnswers, answer)
+			continue
+		}
+		r.guesses += len(game.Results)
+		r.distribution[len(game.Results)]++
+	}
+	r.elapsed = time.Since(start)
+
+	return r, nil
+}
+
+// mean is the average number of guesses in the games that were won
+func (r benchResult) mean() float64 {
+	return float64(r.guesses) / float64(r.games-r.lost)
+}
+
+// perGame is the time taken per game, zero if none were played
+func (r benchResult) perGame() time.Duration {
+	if r.games == 0 {
+		return 0
+	}
+	return r.elapsed / time.Duration(r.games)
+}
+
+// average is the average number of guesses over all games, a lost game counting as 7
+func (r benchResult) average() float64 {
+	return float64(r.guesses+7*r.lost) / float64(r.games)
+}
+
+// Run is the implementation of Bench
+func (b Bench) Run(p *cached.Patterns) (err error) {
+	if p == nil {
+		if p, err = loadPatterns(); err != nil {
+			return err
+		}
+	}
+	answers, err := b.answers()
+	if err != nil {
+		return err
+	}
+	solver, err := newSolver(p)
+	if err != nil {
+		return err
+	}
+
+	replays, err := openReplayLog(b.JSONL)
+	if err != nil {
+		return err
+	}
+	defer replays.Close()
+
+	r, err := runBench(solver, answers, replays)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("strategy: %s, scoring: %s, opener: %s\n", solver.Strategy.Name(), scoring.Name, solver.Opener)
+	fmt.Printf("games: %d, lost: %d\n", r.games, r.lost)
+	for n := 1; n <= 6; n++ {
+		fmt.Printf("%d: %d\n", n, r.distribution[n])
+	}
+	if r.lost < r.games {
+		fmt.Printf("mean guesses (won games): %.4f\n", r.mean())
+	}
+	fmt.Printf("time per game: %s\n", r.perGame())
+
+	// too few or too alike games can't be fitted, that doesn't spoil the rest of the results
+	if curve, err := cached.FitCurve(r.samples); err != nil {
+		fmt.Printf("no curve fitted: %s\n", err)
+	} else {
+		fmt.Printf("fitted curve: %s\n", curve)
+		if b.SaveCurve != "" {
+			if err = curve.Save(b.SaveCurve); err != nil {
+				return err
+			}
+		}
+	}
+
+	if b.Baseline {
+		solver.Strategy = cached.Entropy{}
+		baseline, err := runBench(solver, answers, nil)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("baseline: %s, lost: %d", solver.Strategy.Name(), baseline.lost)
+		if baseline.lost < baseline.games {
+			fmt.Printf(", mean guesses (won games): %.4f", baseline.mean())
+		}
+		fmt.Println()
+		fmt.Printf("losses eliminated: %v\n", difference(baseline.lostAnswers, r.lostAnswers))
+		fmt.Printf("losses introduced: %v\n", difference(r.lostAnswers, baseline.lostAnswers))
+	}
+	return nil
+}
+
+// difference is the words of a that aren't in b
+func difference(a, b primitives.Dictionary) primitives.Dictionary {
+	in := map[primitives.Word]bool{}
+	for _, w := range b {
+		in[w] = true
+	}
+	diff := primitives.Dictionary{}
+	for _, w := range a {
+		if !in[w] {
+			diff = append(diff, w)
+		}
+	}
+	return diff
+}
@@ -0,0 +1,15 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBenchResult_PerGame(t *testing.T) {
+	if got := (benchResult{elapsed: time.Second}).perGame(); got != 0 {
+		t.Errorf("no games took %s each", got)
+	}
+	if got := (benchResult{games: 4, elapsed: time.Second}).perGame(); got != 250*time.Millisecond {
+		t.Errorf("4 games in a second took %s each", got)
+	}
+}
@@ -0,0 +1,283 @@
+package main
+
+import (
+	"bit-wordy/src/answers"
+	"bit-wordy/src/games"
+	"bit-wordy/src/histogram"
+	"bit-wordy/src/primitives"
+	"bit-wordy/src/util"
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Daily is the subcommand to play the day's puzzle, once a day. The answer is derived from
+// the date: puzzle n is the nth word of the --list, or of the words in a fixed shuffle,
+// counting from the --epoch. Statistics and streaks are kept in the --state file, under the
+// user's config directory by default, which has each guess written to it as it is made so a
+// game left unfinished is resumed. An earlier --date can be played for practice, it isn't
+// counted in the statistics.
+type Daily struct {
+	Date  string `arg:"--date"`
+	Epoch string `arg:"--epoch"`
+	List  string `arg:"--list"`
+	State string `arg:"--state"`
+	Stats bool   `arg:"--stats"`
+}
+
+// dailyStats is what is kept between days, the distribution is indexed by guesses-1. The
+// LastGame is the game of the LastDay, which is unfinished while it is being played.
+type dailyStats struct {
+	Played        int                   `json:"played"`
+	Won           int                   `json:"won"`
+	CurrentStreak int                   `json:"current_streak"`
+	MaxStreak     int                   `json:"max_streak"`
+	Distribution  [games.MaxGuesses]int `json:"distribution"`
+	LastDay       *int                  `json:"last_day,omitempty"`
+	LastWonDay    *int                  `json:"last_won_day,omitempty"`
+	LastGame      *games.Game           `json:"last_game,omitempty"`
+}
+
+// unfinished is whether the last game was left before it was over
+func (s dailyStats) unfinished() bool {
+	return s.LastGame != nil && !(s.LastGame.IsWon() || s.LastGame.IsLost())
+}
+
+// catchUp settles the statistics up to today: an unfinished game from an earlier day counts
+// as lost, and the current streak is broken if yesterday's puzzle wasn't won
+func (s *dailyStats) catchUp(today int) {
+	if s.LastDay != nil && *s.LastDay < today && s.unfinished() {
+		s.record(*s.LastDay, s.LastGame)
+	}
+	if s.LastWonDay == nil || *s.LastWonDay < today-1 {
+		s.CurrentStreak = 0
+	}
+}
+
+// record adds the game finished on the day to the statistics
+func (s *dailyStats) record(day int, g *games.Game) {
+	s.Played++
+	s.LastDay, s.LastGame = &day, g
+	if !g.IsWon() {
+		s.CurrentStreak = 0
+		return
+	}
+
+	s.Won++
+	s.Distribution[len(g.Results)-1]++
+	if s.LastWonDay != nil && *s.LastWonDay == day-1 {
+		s.CurrentStreak++
+	} else {
+		s.CurrentStreak = 1
+	}
+	if s.CurrentStreak > s.MaxStreak {
+		s.MaxStreak = s.CurrentStreak
+	}
+	s.LastWonDay = &day
+}
+
+// String is the statistics panel with the guess distribution chart, the bar of the last
+// game is highlighted
+func (s dailyStats) String() string {
+	winRate := 0
+	if s.Played > 0 {
+		winRate = 100 * s.Won / s.Played
+	}
+	text := fmt.Sprintf(
+		"played: %d  win %%: %d  current streak: %d  max streak: %d\n\nGUESS DISTRIBUTION\n",
+		s.Played, winRate, s.CurrentStreak, s.MaxStreak,
+	)
+
+	rows := make([]histogram.Row, len(s.Distribution))
+	for i, count := range s.Distribution {
+		rows[i] = histogram.Row{Label: fmt.Sprint(i + 1), Count: count, Note: fmt.Sprint(count)}
+	}
+	if s.LastGame != nil && s.LastGame.IsWon() {
+		rows[len(s.LastGame.Results)-1].Highlight = true
+	}
+	return text + histogram.Chart(rows, util.Min(histogram.TerminalWidth(), 60))
+}
+
+// statePath is the --state, or daily.json in the user's config directory
+func (d Daily) statePath() (string, error) {
+	if d.State != "" {
+		return d.State, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "bit-wordy", "daily.json"), nil
+}
+
+func loadDailyStats(path string) (dailyStats, error) {
+	s := dailyStats{}
+	content, err := ioutil.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return s, err
+	}
+	return s, json.Unmarshal(content, &s)
+}
+
+func (s dailyStats) save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	content, err := json.MarshalIndent(s, "", "\t")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, content, 0o644)
+}
+
+// source is the day's answers as configured
+func (d Daily) source() (*answers.Daily, error) {
+	var source *answers.Daily
+	if d.List != "" {
+		list, err := primitives.LoadWordsFrom(d.List, alphabet)
+		if err != nil {
+			return nil, err
+		}
+		if _, err = answers.NewList(list, words); err != nil {
+			return nil, err
+		}
+		source = answers.NewDailyList(list, time.Now())
+	} else {
+		// the answers must be the same every day and as --answer daily, so there is no
+		// random seed
+		source = answers.NewDaily(words, answers.DailySeed, time.Now())
+	}
+	if d.Epoch != "" {
+		epoch, err := time.Parse("2006-01-02", d.Epoch)
+		if err != nil {
+			return nil, fmt.Errorf("epoch: %w", err)
+		}
+		source.Epoch = epoch
+	}
+	return source, nil
+}
+
+// Run is the implementation of Daily
+func (d Daily) Run() error {
+	path, err := d.statePath()
+	if err != nil {
+		return err
+	}
+	stats, err := loadDailyStats(path)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", path, err)
+	}
+	source, err := d.source()
+	if err != nil {
+		return err
+	}
+
+	date, practice := time.Now(), d.Date != ""
+	today := source.Number(date)
+	stats.catchUp(today)
+	if practice {
+		if date, err = time.Parse("2006-01-02", d.Date); err != nil {
+			return fmt.Errorf("date: %w", err)
+		}
+		if source.Number(date) >= today {
+			return fmt.Errorf("date: %s is not before today, only earlier puzzles can be practised", d.Date)
+		}
+	}
+	day := source.Number(date)
+	resume := !practice && stats.LastDay != nil && *stats.LastDay == day && stats.unfinished()
+
+	if d.Stats || (!practice && !resume && stats.LastDay != nil && *stats.LastDay == day) {
+		if !d.Stats {
+			fmt.Printf("You have already played puzzle %d today, come back tomorrow\n", day)
+			if stats.LastGame != nil {
+				fmt.Println(stats.LastGame.Results)
+			}
+		}
+		fmt.Print(stats)
+		return nil
+	}
+
+	g := newGame(source.On(date))
+	// practice games aren't kept, the day's game is saved before each guess is shown
+	save := func() error { return nil }
+	if !practice {
+		if resume && stats.LastGame.Answer == g.Answer {
+			g.Results = stats.LastGame.Results
+			fmt.Printf("Resuming puzzle %d\n%s\n", day, g.Results)
+		}
+		stats.LastDay, stats.LastGame = &day, g
+		save = func() error { return stats.save(path) }
+		if err = save(); err != nil {
+			return err
+		}
+	}
+	if err = playDaily(g, day, save); err != nil {
+		return err
+	}
+	if g.IsWon() {
+		fmt.Printf("Solved in %d!\n", len(g.Results))
+	} else {
+		fmt.Printf("The answer was %s\n", g.Answer)
+	}
+	notation, ok := primitives.Notations[args.Share]
+	if !ok {
+		notation = primitives.EmojiNotation
+	}
+	fmt.Printf("\n%s\n", g.Share(games.ShareOptions{Title: "Daily", Number: day, Notation: notation}))
+	if practice {
+		return nil
+	}
+
+	stats.record(day, g)
+	if err = stats.save(path); err != nil {
+		return err
+	}
+	fmt.Print(stats)
+	return nil
+}
+
+// playDaily reads guesses from stdin until the game is over, saving the game after each one
+func playDaily(g *games.Game, day int, save func() error) error {
+	vocab := map[primitives.Word]bool{}
+	for _, w := range words {
+		vocab[w] = true
+	}
+
+	fmt.Printf("Puzzle %d, you have %d guesses left\n", day, games.MaxGuesses-len(g.Results))
+	in := bufio.NewScanner(os.Stdin)
+	for !(g.IsWon() || g.IsLost()) {
+		fmt.Printf("guess %d: ", len(g.Results)+1)
+		if !in.Scan() {
+			if err := in.Err(); err != nil {
+				return err
+			}
+			return fmt.Errorf("no more guesses to read, the game wasn't finished")
+		}
+		guess := strings.TrimSpace(in.Text())
+		word := primitives.MakeWord(guess)
+		switch {
+		case utf8.RuneCountInString(guess) != primitives.WordLength:
+			fmt.Printf("%q is not %d letters long\n", guess, primitives.WordLength)
+			continue
+		case !vocab[word]:
+			fmt.Printf("%s is not in the word list\n", guess)
+			continue
+		}
+		g.Guess(word)
+		if err := save(); err != nil {
+			return err
+		}
+		fmt.Println(g.Results)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bit-wordy/src/games"
+	"bit-wordy/src/primitives"
+	"testing"
+)
+
+// dailyGame is a game of light with the guesses
+func dailyGame(guesses ...string) *games.Game {
+	g := games.NewGame(primitives.MakeWord("light"))
+	for _, guess := range guesses {
+		g.Guess(primitives.MakeWord(guess))
+	}
+	return g
+}
+
+func TestDailyStats_Record(t *testing.T) {
+	won := func(n int) *games.Game {
+		guesses := []string{"tares", "night", "might", "fight", "sight"}[:n-1]
+		return dailyGame(append(guesses, "light")...)
+	}
+	lost := dailyGame("tares", "night", "might", "fight", "sight", "tight")
+
+	tests := []struct {
+		day           int
+		game          *games.Game
+		current, max  int
+		played, count int
+	}{
+		{10, won(3), 1, 1, 1, 1},
+		{11, won(2), 2, 2, 2, 1},
+		{12, won(3), 3, 3, 3, 2},
+		{13, lost, 0, 3, 4, 0},
+		{14, won(6), 1, 3, 5, 1},
+		// a day was missed
+		{16, won(3), 1, 3, 6, 3},
+		{17, won(1), 2, 3, 7, 1},
+	}
+	s := dailyStats{}
+	for _, tt := range tests {
+		s.record(tt.day, tt.game)
+		if s.CurrentStreak != tt.current || s.MaxStreak != tt.max || s.Played != tt.played {
+			t.Errorf("day %d: streak %d, max %d, played %d, want %d, %d, %d", tt.day, s.CurrentStreak, s.MaxStreak, s.Played, tt.current, tt.max, tt.played)
+		}
+		if tt.game.IsWon() && s.Distribution[len(tt.game.Results)-1] != tt.count {
+			t.Errorf("day %d: distribution %v", tt.day, s.Distribution)
+		}
+		if *s.LastDay != tt.day || s.LastGame != tt.game {
+			t.Errorf("day %d: last game was day %d", tt.day, *s.LastDay)
+		}
+	}
+	if s.Won != 6 || *s.LastWonDay != 17 {
+		t.Errorf("won %d, last on day %d, want 6 and 17", s.Won, *s.LastWonDay)
+	}
+}
+
+func TestDailyStats_CatchUp(t *testing.T) {
+	day := func(d int) *int { return &d }
+	tests := []struct {
+		name    string
+		stats   dailyStats
+		today   int
+		current int
+		played  int
+	}{
+		{"won yesterday", dailyStats{Played: 2, CurrentStreak: 2, LastDay: day(9), LastWonDay: day(9), LastGame: dailyGame("light")}, 10, 2, 2},
+		{"won today", dailyStats{Played: 2, CurrentStreak: 2, LastDay: day(10), LastWonDay: day(10), LastGame: dailyGame("light")}, 10, 2, 2},
+		{"stale streak", dailyStats{Played: 2, CurrentStreak: 2, LastDay: day(8), LastWonDay: day(8), LastGame: dailyGame("light")}, 10, 0, 2},
+		{"never won", dailyStats{Played: 1, LastDay: day(9), LastGame: dailyGame("tares", "night", "might", "fight", "sight", "tight")}, 10, 0, 1},
+		{"unfinished today", dailyStats{Played: 1, CurrentStreak: 1, LastDay: day(10), LastWonDay: day(9), LastGame: dailyGame("tares")}, 10, 1, 1},
+		{"unfinished yesterday", dailyStats{Played: 1, CurrentStreak: 1, LastDay: day(9), LastWonDay: day(8), LastGame: dailyGame("tares")}, 10, 0, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.stats.catchUp(tt.today)
+			if tt.stats.CurrentStreak != tt.current || tt.stats.Played != tt.played {
+				t.Errorf("streak %d, played %d, want %d and %d", tt.stats.CurrentStreak, tt.stats.Played, tt.current, tt.played)
+			}
+		})
+	}
+}
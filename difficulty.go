@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bit-wordy/src/cached"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+)
+
+// Difficulty is the subcommand that ranks every answer by how hard the selected strategy
+// finds it, then reports the families of answers differing in one position (the _ight trap)
+// that cost the most guesses. --json writes the full ranking and every cluster.
+type Difficulty struct {
+	Top        int    `arg:"--top" default:"30"`
+	Clusters   int    `arg:"--clusters" default:"15"`
+	MinCluster int    `arg:"--min-cluster" default:"3"`
+	JSON       string `arg:"--json"`
+}
+
+// Run is the implementation of Difficulty
+func (d Difficulty) Run(p *cached.Patterns) (err error) {
+	if p == nil {
+		if p, err = loadPatterns(); err != nil {
+			return err
+		}
+	}
+	solver, err := newSolver(p)
+	if err != nil {
+		return err
+	}
+
+	ranking, err := solver.Difficulties(scoring)
+	if err != nil {
+		return err
+	}
+	clusters := cached.Clusters(ranking, d.MinCluster)
+
+	lost := 0
+	for _, r := range ranking {
+		if r.Lost {
+			lost++
+		}
+	}
+	fmt.Printf("strategy: %s, scoring: %s, opener: %s, %d answers, %d lost\n\n", solver.Strategy.Name(), scoring.Name, solver.Opener, len(ranking), lost)
+	fmt.Printf("%-5s  %7s  %13s  %10s\n", "word", "guesses", "opener bucket", "neighbours")
+	for i, r := range ranking {
+		if i == d.Top {
+			break
+		}
+		fmt.Println(r)
+	}
+
+	fmt.Printf("\n%-6s  %4s  %4s  %5s  %s\n", "family", "size", "lost", "mean", "members, hardest first")
+	for i, c := range clusters {
+		if i == d.Clusters {
+			break
+		}
+		fmt.Println(c)
+	}
+
+	if d.JSON == "" {
+		return nil
+	}
+	content, err := json.MarshalIndent(struct {
+		Ranking  []cached.Difficulty `json:"ranking"`
+		Clusters []cached.Cluster    `json:"clusters"`
+	}{ranking, clusters}, "", "\t")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(d.JSON, content, 0o644)
+}
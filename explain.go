@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bit-wordy/src/cached"
+	"bit-wordy/src/histogram"
+	"bit-wordy/src/primitives"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Explain is the subcommand that shows how a guess splits the candidate answers: each
+// pattern it could give, with the number of candidates, their probability, the information
+// in bits and some of the words. The candidates are the answers left by the history, given
+// as results such as tares:gy..g. The chart fits the terminal unless --width is given, and
+// can be written as csv or svg instead with --format.
+type Explain struct {
+	Guess   string   `arg:"positional,required"`
+	History []string `arg:"positional"`
+	Samples int      `arg:"--samples" default:"5"`
+	Width   int      `arg:"--width"`
+	Format  string   `arg:"--format" default:"text"`
+	Output  string   `arg:"-o,--output"`
+}
+
+// Run is the implementation of Explain
+func (e Explain) Run(p *cached.Patterns) (err error) {
+	if p == nil {
+		if p, err = loadPatterns(); err != nil {
+			return err
+		}
+	}
+
+	results := primitives.ResultSet{}
+	for _, h := range e.History {
+		result, err := primitives.ParseResult(h)
+		if err != nil {
+			return err
+		}
+		results = append(results, result)
+	}
+	remaining, err := p.PruneHistory(results)
+	if err != nil {
+		return err
+	}
+	guess := primitives.MakeWord(e.Guess)
+	row, ok := remaining.GuessRow(guess)
+	if !ok {
+		return fmt.Errorf("guess %s is not in the vocabulary", e.Guess)
+	}
+
+	candidates := make(primitives.ResultSet, len(row))
+	for ansId, b := range row {
+		candidates[ansId] = primitives.Result{Word: remaining.Vocab[ansId], Pattern: scoring.Decode(b)}
+	}
+	h := histogram.New(candidates)
+
+	var out io.Writer = os.Stdout
+	if e.Output != "" {
+		file, err := os.Create(e.Output)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		out = file
+	}
+
+	title := fmt.Sprintf(
+		"%s against %d candidates: %d patterns, %.2f bits expected",
+		guess, len(remaining.Vocab), len(h), h.Entropy(),
+	)
+	switch e.Format {
+	case "text":
+		width := e.Width
+		if width <= 0 {
+			width = histogram.TerminalWidth()
+		}
+		_, err = fmt.Fprintf(out, "%s\n%s", title, h.Render(width, e.Samples))
+	case "csv":
+		err = h.WriteCSV(out, e.Samples)
+	case "svg":
+		err = h.WriteSVG(out, title, e.Samples)
+	default:
+		err = fmt.Errorf("unknown format %q, use text, csv or svg", e.Format)
+	}
+	return err
+}
@@ -6,11 +6,11 @@ require (
 	github.com/alexflint/go-arg v1.4.3
 	github.com/fatih/color v1.13.0
 	github.com/kelindar/binary v1.0.17
+	golang.org/x/sys v0.0.0-20210630005230-0f9fa26af87c
 )
 
 require (
 	github.com/alexflint/go-scalar v1.1.0 // indirect
 	github.com/mattn/go-colorable v0.1.9 // indirect
 	github.com/mattn/go-isatty v0.0.14 // indirect
-	golang.org/x/sys v0.0.0-20210630005230-0f9fa26af87c // indirect
 )
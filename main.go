@@ -1,23 +1,209 @@
 package main
 
 import (
+	"bit-wordy/src/answers"
 	"bit-wordy/src/cached"
 	"bit-wordy/src/games"
+	"bit-wordy/src/nerdle"
+	"bit-wordy/src/primel"
 	"bit-wordy/src/primitives"
+	"bit-wordy/src/priors"
 	"fmt"
 	"github.com/alexflint/go-arg"
 	"log"
+	"math/rand"
 	"time"
 )
 
-const Cache = "data/cache"
+const (
+	Cache = "data/cache"
+	// Words is the word list of the wordle variant
+	Words = "data/words"
+)
+
+var (
+	// words is the dictionary of the selected variant, it is loaded by selectVariant
+	words    primitives.Dictionary
+	alphabet = primitives.Latin
+	cache    = Cache
+	scoring  = primitives.Classic
+	// prior is the answer likelihood from --frequencies, nil when every answer is equally likely
+	prior    priors.Prior
+	strategy cached.Strategy = cached.Entropy{}
+)
+
+// variant is a playable dictionary and the path its pattern cache lives at
+type variant struct {
+	load  func() (primitives.Dictionary, error)
+	cache string
+}
+
+var variants = map[string]variant{
+	"wordle": {func() (primitives.Dictionary, error) { return primitives.LoadWordsFrom(Words, primitives.Latin) }, Cache},
+	"primel": {func() (primitives.Dictionary, error) { return primel.Dictionary(), nil }, primel.Cache},
+}
+
+// selectVariant loads the words, and picks the cache and scoring, chosen on the command line,
+// a custom words file takes precedence over the named variant. Each feedback model other
+// than classic gets its own cache alongside the variant's.
+func selectVariant() error {
+	var ok bool
+	alphabet, ok = primitives.Alphabets[args.Alphabet]
+	if !ok {
+		return fmt.Errorf("unknown alphabet %q", args.Alphabet)
+	}
+	if args.Variant == "primel" {
+		alphabet = primitives.Digits
+	}
+
+	switch {
+	case args.Words != "":
+		dict, err := primitives.LoadWordsFrom(args.Words, alphabet)
+		if err != nil {
+			return err
+		}
+		words, cache = dict, args.Words+".cache"
+	case args.Nerdle != nil:
+		// nerdle generates its own equations, there is no word list to load
+	default:
+		v, ok := variants[args.Variant]
+		if !ok {
+			return fmt.Errorf("unknown variant %q", args.Variant)
+		}
+		dict, err := v.load()
+		if err != nil {
+			return err
+		}
+		words, cache = dict, v.cache
+	}
+
+	switch args.Scoring {
+	case primitives.Classic.Name:
+		scoring = primitives.Classic
+	case primitives.Peaks.Name:
+		scoring = primitives.PeaksScoring(alphabet)
+		cache += "-" + scoring.Name
+	default:
+		return fmt.Errorf("unknown scoring %q", args.Scoring)
+	}
+	return nil
+}
+
+// selectPrior loads the --frequencies file, if any, into a prior over the words
+func selectPrior() error {
+	prior = nil
+	if args.Frequencies == "" {
+		return nil
+	}
+	freqs, err := priors.LoadFrequencies(args.Frequencies)
+	if err != nil {
+		return err
+	}
+	prior = priors.Sigmoid(freqs, words, priors.DefaultCentre, priors.DefaultWidth)
+	return nil
+}
+
+// loadPatterns loads the selected cache, weighted by the prior
+func loadPatterns() (*cached.Patterns, error) {
+	p, err := cached.LoadPatternsFrom(cache, words)
+	if err != nil {
+		return nil, err
+	}
+	if prior != nil {
+		p.SetWeights(prior)
+	}
+	return p, nil
+}
+
+// selectStrategy picks the --strategy the solvers use
+func selectStrategy() (err error) {
+	strategy, err = parseStrategy(args.Strategy)
+	return err
+}
+
+// strategies are the strategies that can be named, in the order the tournament plays them
+// by default. The expected score strategy uses the --curve if given, the trap strategy plays
+// by --hard and the weighted strategy needs the --frequencies prior.
+var strategies = []struct {
+	name string
+	make func() (cached.Strategy, error)
+	// needsPrior is set if the strategy can't be made without --frequencies
+	needsPrior bool
+}{
+	{cached.Entropy{}.Name(), func() (cached.Strategy, error) { return cached.Entropy{}, nil }, false},
+	{cached.Minimax{}.Name(), func() (cached.Strategy, error) { return cached.Minimax{}, nil }, false},
+	{cached.ExpectedScore{}.Name(), func() (cached.Strategy, error) {
+		if args.Curve == "" {
+			return cached.ExpectedScore{Curve: cached.DefaultCurve}, nil
+		}
+		curve, err := cached.LoadCurve(args.Curve)
+		if err != nil {
+			return nil, err
+		}
+		return cached.ExpectedScore{Curve: curve}, nil
+	}, false},
+	{cached.TrapAware{}.Name(), func() (cached.Strategy, error) { return cached.TrapAware{HardMode: args.Hard}, nil }, false},
+	{cached.Lookahead{}.Name(), func() (cached.Strategy, error) { return cached.Lookahead{}, nil }, false},
+	{cached.Weighted{}.Name(), func() (cached.Strategy, error) {
+		if prior == nil {
+			return nil, fmt.Errorf("the weighted strategy needs --frequencies")
+		}
+		return cached.Weighted{Strategy: cached.Entropy{}, Prior: prior}, nil
+	}, true},
+}
+
+// strategyNames is the name of each of the strategies
+func strategyNames() []string {
+	names := make([]string, len(strategies))
+	for i, s := range strategies {
+		names[i] = s.name
+	}
+	return names
+}
+
+// parseStrategy returns the strategy with the name
+func parseStrategy(name string) (cached.Strategy, error) {
+	for _, s := range strategies {
+		if s.name == name {
+			return s.make()
+		}
+	}
+	return nil, fmt.Errorf("unknown strategy %q, want one of %v", name, strategyNames())
+}
+
+// newSolver returns a solver using the selected strategy and --opener
+func newSolver(p *cached.Patterns) (*cached.FastSolver, error) {
+	s := cached.NewSolver(p)
+	s.Strategy = strategy
+	if args.Opener != "" {
+		if err := s.SetOpener(primitives.MakeWord(args.Opener)); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// newGame starts a game scored by the selected feedback model
+func newGame(answer primitives.Word) *games.Game {
+	return games.NewGameWith(answer, scoring)
+}
 
-var words = primitives.LoadWords()
+// seed is the --seed, or the time if none was given
+func seed() int64 {
+	if args.Seed != nil {
+		return *args.Seed
+	}
+	return time.Now().UnixNano()
+}
 
-// chooseAnswer is a lazy pseudo-random answer chooser implementation
-// that turns out to be good enough for our purposes.
-func chooseAnswer() primitives.Word {
-	return words[int(time.Now().UnixMilli())%len(words)]
+// answerSource picks the answers as chosen by --answer, see answers.Parse. Random answers
+// are drawn from the prior instead when samplePrior is set and there are --frequencies.
+func answerSource(samplePrior bool) (answers.Source, error) {
+	if samplePrior && prior != nil && (args.Answer == "" || args.Answer == "random") {
+		rng := rand.New(rand.NewSource(seed()))
+		return answers.Func(priors.NewSampler(words, prior, rng).Sample), nil
+	}
+	return answers.Parse(args.Answer, words, alphabet, seed())
 }
 
 type pair[T any] [2]T
@@ -39,6 +225,11 @@ func (g *Guess) Run(p *cached.Patterns) {
 	ans := primitives.MakeWord(g.Ans)
 	results := checkGuess(ans, guess)
 	fmt.Printf("FRESH: %s ∙ %s\n", results[1], results[0])
+	fmt.Printf("NOTATION: %s %s %s\n",
+		results[0].Pattern.Format(primitives.LetterNotation),
+		results[0].Pattern.Format(primitives.DigitNotation),
+		results[0].Pattern.Format(primitives.EmojiNotation),
+	)
 	if p != nil {
 		results = fromCache(p, ans, guess)
 		fmt.Printf("CACHE: %s ∙ %s\n", results[1], results[0])
@@ -46,25 +237,35 @@ func (g *Guess) Run(p *cached.Patterns) {
 }
 
 // Iterate is the subcommand that allows the user to supply a number of games to be solved
-// the --print option controls whether we print each game outcome to stdout.
+// the --print option controls whether we print each game outcome to stdout, and
+// --sample-prior draws the answers from the --frequencies prior instead of uniformly.
 type Iterate struct {
-	Times int  `arg:"positional"`
-	Print bool `arg:"-p, --print"`
+	Times       int    `arg:"positional"`
+	Print       bool   `arg:"-p, --print"`
+	SamplePrior bool   `arg:"--sample-prior"`
+	JSONL       string `arg:"--jsonl"`
 }
 
 // Run is the implementation of Iter
 func (i Iterate) Run(p *cached.Patterns) (err error) {
 	if !args.Load {
-		p, err = cached.LoadPatterns(words)
+		p, err = loadPatterns()
 		if err != nil {
 			return err
 		}
 	}
+	source, err := answerSource(i.SamplePrior)
+	if err != nil {
+		return err
+	}
 
+	solver, err := newSolver(p)
+	if err != nil {
+		return err
+	}
 	var (
-		game   = games.NewGame(chooseAnswer())
+		game   *games.Game
 		answer primitives.Word
-		solver = cached.NewSolver(p)
 	)
 	prin := func(d time.Duration, g *games.Game) {}
 	if i.Print {
@@ -74,26 +275,124 @@ func (i Iterate) Run(p *cached.Patterns) (err error) {
 		}
 
 	}
+	replays, err := openReplayLog(i.JSONL)
+	if err != nil {
+		return err
+	}
+	defer replays.Close()
+
 	start := time.Now()
+	guesses, lost := 0, 0
 	for j := 0; j < i.Times; j++ {
 		solver.Reset()
-		answer = chooseAnswer()
-		game.Reset(answer)
-		game = games.NewGame(answer)
-		_, playDuration := solver.Solve(game)
+		answer = source.Next()
+		game = newGame(answer)
+		_, playDuration, err := solver.Solve(game)
+		if err != nil {
+			return err
+		}
 		prin(playDuration, game)
+		if err = replays.Write(solver.Replay(game, playDuration)); err != nil {
+			return err
+		}
+		guesses += len(game.Results)
+		if game.IsLost() {
+			lost++
+		}
 	}
+	fmt.Printf("%s scoring: %.3f guesses on average, %d lost\n", scoring.Name, float64(guesses)/float64(i.Times), lost)
 	fmt.Println(time.Now().Sub(start) / time.Duration(i.Times))
 	return err
 }
 
+// Nerdle is the subcommand that solves equation guessing games, either the equation given
+// as the --answer or Times randomly chosen equations
+type Nerdle struct {
+	Times int  `arg:"positional"`
+	Print bool `arg:"-p, --print"`
+}
+
+// Run is the implementation of Nerdle
+func (n Nerdle) Run() error {
+	candidates := nerdle.Generate()
+	rng := rand.New(rand.NewSource(seed()))
+	equations := make([]nerdle.Equation, n.Times)
+	for j := range equations {
+		equations[j] = candidates[rng.Intn(len(candidates))]
+	}
+	if args.Answer != "" {
+		if err := nerdle.Validate(args.Answer); err != nil {
+			return err
+		}
+		equations = []nerdle.Equation{nerdle.MakeEquation(args.Answer)}
+	}
+	if len(equations) == 0 {
+		return fmt.Errorf("no games to play, give a number of games or an --answer")
+	}
+
+	var (
+		solver       = nerdle.NewSolver(candidates)
+		distribution = make([]int, nerdle.MaxGuesses+1)
+		start        = time.Now()
+	)
+	for _, answer := range equations {
+		g, playDuration, err := solver.Solve(nerdle.NewGame(answer))
+		if err != nil {
+			return err
+		}
+		if n.Print || args.Answer != "" {
+			fmt.Printf("TIME: %s\n", playDuration.String())
+			fmt.Printf("GAME:\n%s\n", g)
+		}
+		if g.IsWon() {
+			distribution[len(g.Results)]++
+		} else {
+			distribution[0]++
+		}
+	}
+	for guesses := 1; guesses <= nerdle.MaxGuesses; guesses++ {
+		fmt.Printf("%d: %d\n", guesses, distribution[guesses])
+	}
+	fmt.Printf("X: %d\n", distribution[0])
+	fmt.Println(time.Now().Sub(start) / time.Duration(len(equations)))
+	return nil
+}
+
 var args struct {
-	Build bool     `arg:"-b,--build"`
-	Dump  bool     `arg:"-d,--dump"`
-	Load  bool     `arg:"-l,--load"`
-	Play  bool     `arg:"-p,--play"`
-	Guess *Guess   `arg:"subcommand:guess"`
-	Iter  *Iterate `arg:"subcommand:iter"`
+	Build       bool        `arg:"-b,--build"`
+	Dump        bool        `arg:"-d,--dump"`
+	Load        bool        `arg:"-l,--load"`
+	Play        bool        `arg:"-p,--play"`
+	Variant     string      `arg:"--variant" default:"wordle"`
+	Words       string      `arg:"--words"`
+	Alphabet    string      `arg:"--alphabet" default:"latin"`
+	Scoring     string      `arg:"--scoring" default:"classic"`
+	Frequencies string      `arg:"--frequencies"`
+	Strategy    string      `arg:"--strategy" default:"entropy"`
+	Curve       string      `arg:"--curve"`
+	Opener      string      `arg:"--opener"`
+	Share       string      `arg:"--share"`
+	Save        string      `arg:"--save"`
+	Seed        *int64      `arg:"--seed"`
+	Answer      string      `arg:"--answer"`
+	Hard        bool        `arg:"--hard"`
+	Trace       string      `arg:"--trace"`
+	TraceTop    int         `arg:"--trace-top" default:"5"`
+	Guess       *Guess      `arg:"subcommand:guess"`
+	Iter        *Iterate    `arg:"subcommand:iter"`
+	Nerdle      *Nerdle     `arg:"subcommand:nerdle"`
+	Bench       *Bench      `arg:"subcommand:bench"`
+	Openers     *Openers    `arg:"subcommand:openers"`
+	Sequences   *Sequences  `arg:"subcommand:sequences"`
+	Reverse     *Reverse    `arg:"subcommand:reverse"`
+	Serve       *Serve      `arg:"subcommand:serve"`
+	Replay      *Replay     `arg:"subcommand:replay"`
+	Analyse     *Analyse    `arg:"subcommand:analyse"`
+	Explain     *Explain    `arg:"subcommand:explain"`
+	Verify      *Verify     `arg:"subcommand:verify"`
+	Difficulty  *Difficulty `arg:"subcommand:difficulty"`
+	Tournament  *Tournament `arg:"subcommand:tournament"`
+	Daily       *Daily      `arg:"subcommand:daily"`
 }
 
 func main() {
@@ -102,22 +401,28 @@ func main() {
 		err error
 	)
 	arg.MustParse(&args)
+	if err = configure(); err != nil {
+		log.Fatal(err)
+	}
 
 	if args.Build {
 		log.Println("Building...")
-		p = cached.BuildPatterns(words)
+		p = cached.BuildPatternsWith(words, scoring)
+		if prior != nil {
+			p.SetWeights(prior)
+		}
 		log.Println("Built!")
 	}
 	if args.Dump {
 		log.Println("Dumping...")
-		if err = p.Dump(Cache); err != nil {
+		if err = p.Dump(cache); err != nil {
 			log.Fatal(err)
 		}
 		log.Println("Dumped!")
 	}
 	if args.Load {
 		log.Println("Loading...")
-		p, err = cached.LoadPatterns(words)
+		p, err = loadPatterns()
 		if err != nil {
 			log.Fatal(err)
 		}
@@ -126,13 +431,39 @@ func main() {
 	if args.Play {
 		log.Println("Playing...")
 
-		answer := chooseAnswer()
+		source, err := answerSource(false)
+		if err != nil {
+			log.Fatal(err)
+		}
+		answer := source.Next()
 		fmt.Printf("Chose answer: %s\n", answer)
-		g, s, playDuration := solveOne(answer, p)
+		g, s, playDuration, err := solveOne(answer, p)
+		if err != nil {
+			log.Fatal(err)
+		}
 
 		fmt.Printf("%s\n", playDuration.String())
 		fmt.Printf("GAME:\n%s\n", g)
-		fmt.Printf("SOLVER:\n%s", s)
+		if err = tracePlay(p, g); err != nil {
+			log.Fatal(err)
+		}
+		if args.Trace == "" {
+			fmt.Printf("SOLVER:\n%s", s)
+		}
+		if args.Share != "" {
+			notation, ok := primitives.Notations[args.Share]
+			if !ok {
+				log.Fatalf("unknown share notation %q", args.Share)
+			}
+			fmt.Printf("SHARE:\n%s", g.Share(games.ShareOptions{Notation: notation}))
+		}
+		if args.Save != "" {
+			replay := s.Replay(g, playDuration)
+			replay.Scoring = scoring.Name
+			if err = saveReplay(args.Save, replay); err != nil {
+				log.Fatal(err)
+			}
+		}
 
 		log.Println("Played!")
 	}
@@ -145,26 +476,137 @@ func main() {
 			log.Fatal(err)
 		}
 	}
+	if args.Bench != nil {
+		if err = args.Bench.Run(p); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if args.Openers != nil {
+		if err = args.Openers.Run(p); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if args.Sequences != nil {
+		if err = args.Sequences.Run(p); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if args.Reverse != nil {
+		if err = args.Reverse.Run(p); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if args.Serve != nil {
+		if err = args.Serve.Run(p); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if args.Replay != nil {
+		if err = args.Replay.Run(); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if args.Analyse != nil {
+		if err = args.Analyse.Run(p); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if args.Explain != nil {
+		if err = args.Explain.Run(p); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if args.Verify != nil {
+		if err = args.Verify.Run(p); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if args.Difficulty != nil {
+		if err = args.Difficulty.Run(p); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if args.Tournament != nil {
+		if err = args.Tournament.Run(p); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if args.Daily != nil {
+		if err = args.Daily.Run(); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if args.Nerdle != nil {
+		if err = args.Nerdle.Run(); err != nil {
+			log.Fatal(err)
+		}
+	}
 
 	fmt.Println("Done!")
 }
 
-func solveOne(answer primitives.Word, p *cached.Patterns) (*games.Game, *cached.FastSolver, time.Duration) {
-	g := games.NewGame(answer)
-	s := cached.NewSolver(p)
-	g, playDuration := s.Solve(g)
-	s.Reset()
-	return g, s, playDuration
+// configure selects everything the command line chose before any subcommand runs
+func configure() error {
+	for _, selectOption := range []func() error{selectVariant, selectPrior, selectStrategy, checkTrace} {
+		if err := selectOption(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkTrace rejects an unknown --trace format before any game is played
+func checkTrace() error {
+	switch args.Trace {
+	case "", "text", "markdown":
+		return nil
+	default:
+		return fmt.Errorf("unknown trace format %q, expected text or markdown", args.Trace)
+	}
+}
+
+// tracePlay prints the --trace of the game played, in the terminal or as Markdown, see
+// cached.Explain
+func tracePlay(p *cached.Patterns, g *games.Game) error {
+	if args.Trace == "" {
+		return nil
+	}
+	opts := cached.DefaultExplainOptions
+	opts.Top = args.TraceTop
+	e, err := p.Explain(g, opts)
+	if err != nil {
+		return err
+	}
+	if args.Trace == "markdown" {
+		notation, ok := primitives.Notations[args.Share]
+		if !ok {
+			notation = primitives.EmojiNotation
+		}
+		fmt.Printf("TRACE:\n%s", e.Markdown(notation))
+		return nil
+	}
+	fmt.Printf("TRACE:\n%s", e)
+	return nil
+}
+
+func solveOne(answer primitives.Word, p *cached.Patterns) (*games.Game, *cached.FastSolver, time.Duration, error) {
+	g := newGame(answer)
+	s, err := newSolver(p)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	g, playDuration, err := s.Solve(g)
+	return g, s, playDuration, err
 }
 
 func checkGuess(ans primitives.Word, guess primitives.Word) []primitives.Result {
 	results := []primitives.Result{
 		{
-			Pattern: ans.CheckGuess(guess),
+			Pattern: scoring.Check(ans, guess),
 			Word:    guess,
 		},
 		{
-			Pattern: ans.CheckGuess(guess),
+			Pattern: scoring.Check(ans, guess),
 			Word:    ans,
 		},
 	}
@@ -174,11 +616,11 @@ func checkGuess(ans primitives.Word, guess primitives.Word) []primitives.Result
 func fromCache(p *cached.Patterns, ans, guess primitives.Word) []primitives.Result {
 	results := []primitives.Result{
 		{
-			Pattern: p.Compare(guess, ans),
+			Pattern: scoring.Decode(p.Compare(guess, ans).Byte()),
 			Word:    guess,
 		},
 		{
-			Pattern: p.Compare(guess, ans),
+			Pattern: scoring.Decode(p.Compare(guess, ans).Byte()),
 			Word:    ans,
 		},
 	}
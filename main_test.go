@@ -3,9 +3,41 @@ package main
 import (
 	"bit-wordy/src/cached"
 	"bit-wordy/src/primitives"
+	"github.com/alexflint/go-arg"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 )
 
+// wordsFile writes the words to a words file for --words
+func wordsFile(tb testing.TB, words ...string) string {
+	tb.Helper()
+	path := filepath.Join(tb.TempDir(), "words")
+	if err := os.WriteFile(path, []byte(strings.Join(words, "\n")+"\n"), 0o644); err != nil {
+		tb.Fatal(err)
+	}
+	return path
+}
+
+// parseArgs sets the args as if the command line was given and configures the variant,
+// prior and strategy it selects
+func parseArgs(tb testing.TB, cmdline ...string) {
+	tb.Helper()
+	reflect.ValueOf(&args).Elem().Set(reflect.Zero(reflect.TypeOf(args)))
+	parser, err := arg.NewParser(arg.Config{}, &args)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	if err = parser.Parse(cmdline); err != nil {
+		tb.Fatal(err)
+	}
+	if err = configure(); err != nil {
+		tb.Fatal(err)
+	}
+}
+
 func TestIterate_Run(t *testing.T) {
 	type fields struct {
 		Times int
@@ -13,6 +45,7 @@ func TestIterate_Run(t *testing.T) {
 	type args struct {
 		p *cached.Patterns
 	}
+	parseArgs(t)
 	a := args{}
 	var err error
 	a.p, err = cached.LoadPatterns(primitives.LoadWords())
@@ -39,3 +72,27 @@ func TestIterate_Run(t *testing.T) {
 		})
 	}
 }
+
+// TestArgs_Top checks the subcommands' --top isn't shadowed by a top level flag
+func TestArgs_Top(t *testing.T) {
+	path := wordsFile(t, "tares", "light")
+	tests := []struct {
+		cmdline []string
+		top     func() int
+	}{
+		{[]string{"openers"}, func() int { return args.Openers.Top }},
+		{[]string{"sequences"}, func() int { return args.Sequences.Top }},
+		{[]string{"reverse", "-"}, func() int { return args.Reverse.Top }},
+		{[]string{"difficulty"}, func() int { return args.Difficulty.Top }},
+	}
+	for _, tt := range tests {
+		parseArgs(t, append([]string{"--words", path}, append(tt.cmdline, "--top", "3")...)...)
+		if top := tt.top(); top != 3 {
+			t.Errorf("%s --top 3 gave a top of %d", tt.cmdline[0], top)
+		}
+	}
+	parseArgs(t, "--words", path, "--trace", "text", "--trace-top", "3")
+	if args.TraceTop != 3 {
+		t.Errorf("--trace-top 3 gave a top of %d", args.TraceTop)
+	}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bit-wordy/src/cached"
+	"bit-wordy/src/primitives"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Openers is the subcommand that ranks every allowed guess as the first word of a game by its
+// entropy and its worst case, the largest group of answers sharing a pattern, and shows the
+// number of answers expected to be left after it, weighted by the prior if there is one. The best --bench
+// of them are then benchmarked over every answer (or --games random ones) when followed by the
+// selected strategy. --sort is one of entropy, minimax or average.
+type Openers struct {
+	Top   int    `arg:"--top" default:"20"`
+	Bench int    `arg:"--bench" default:"10"`
+	Games int    `arg:"--games"`
+	Sort  string `arg:"--sort" default:"entropy"`
+}
+
+// openerScore is a row of the leaderboard
+type openerScore struct {
+	word     primitives.Word
+	entropy  float64
+	worst    int
+	expected float64
+	bench    *benchResult
+}
+
+// average is the benchmark average, or +Inf if the opener wasn't benchmarked
+func (o openerScore) average() float64 {
+	if o.bench == nil {
+		return math.Inf(1)
+	}
+	return o.bench.average()
+}
+
+var openerOrders = map[string]func(a, b openerScore) bool{
+	"entropy": func(a, b openerScore) bool {
+		return a.entropy > b.entropy
+	},
+	"minimax": func(a, b openerScore) bool {
+		if a.worst == b.worst {
+			return a.entropy > b.entropy
+		}
+		return a.worst < b.worst
+	},
+	"average": func(a, b openerScore) bool {
+		if a.average() == b.average() {
+			return a.entropy > b.entropy
+		}
+		return a.average() < b.average()
+	},
+}
+
+// Run is the implementation of Openers
+func (o Openers) Run(p *cached.Patterns) (err error) {
+	order, ok := openerOrders[o.Sort]
+	if !ok {
+		return fmt.Errorf("unknown sort %q, expected entropy, minimax or average", o.Sort)
+	}
+	if p == nil {
+		if p, err = loadPatterns(); err != nil {
+			return err
+		}
+	}
+
+	entropies, worstCases, expected := p.Entropies(), p.WorstCases(), p.ExpectedRemaining()
+	scores := make([]openerScore, len(p.Vocab))
+	for guessId, word := range p.Vocab {
+		scores[guessId] = openerScore{
+			word: word, entropy: entropies[guessId], worst: worstCases[guessId], expected: expected[guessId],
+		}
+	}
+
+	// benchmarking is expensive, so only the best by the static measure get benchmarked
+	static := order
+	if o.Sort == "average" {
+		static = openerOrders["entropy"]
+	}
+	sort.SliceStable(scores, func(i, j int) bool { return static(scores[i], scores[j]) })
+
+	answers, err := Bench{Games: o.Games}.answers()
+	if err != nil {
+		return err
+	}
+	for i := 0; i < o.Bench && i < len(scores); i++ {
+		solver, err := newSolver(p)
+		if err != nil {
+			return err
+		}
+		if err = solver.SetOpener(scores[i].word); err != nil {
+			return err
+		}
+		r, err := runBench(solver, answers, nil)
+		if err != nil {
+			return err
+		}
+		scores[i].bench = &r
+	}
+	sort.SliceStable(scores, func(i, j int) bool { return order(scores[i], scores[j]) })
+
+	fmt.Printf("strategy: %s, scoring: %s, benchmarked over %d games\n", strategy.Name(), scoring.Name, len(answers))
+	fmt.Printf("%4s  %-6s  %7s  %5s  %8s  %7s  %4s\n", "rank", "opener", "entropy", "worst", "expected", "average", "lost")
+	for rank, s := range scores {
+		if rank >= o.Top {
+			break
+		}
+		average, lost := "-", "-"
+		if s.bench != nil {
+			average, lost = fmt.Sprintf("%.4f", s.average()), fmt.Sprint(s.bench.lost)
+		}
+		fmt.Printf("%4d  %-6s  %7.4f  %5d  %8.2f  %7s  %4s\n", rank+1, s.word, s.entropy, s.worst, s.expected, average, lost)
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bit-wordy/src/cached"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// captureStdout returns what run prints
+func captureStdout(t *testing.T, run func() error) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	output := make(chan string)
+	go func() {
+		b, _ := io.ReadAll(r)
+		output <- string(b)
+	}()
+	err = run()
+	os.Stdout = stdout
+	w.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return <-output
+}
+
+func TestOpeners_Run(t *testing.T) {
+	path := wordsFile(t, "tares", "light", "might", "night", "sight", "crane", "batch", "match", "pious", "zesty")
+	tests := []struct {
+		sort string
+		less func(a, b []string) bool
+	}{
+		{"entropy", func(a, b []string) bool { return number(t, a[2]) > number(t, b[2]) }},
+		{"minimax", func(a, b []string) bool { return number(t, a[3]) < number(t, b[3]) }},
+	}
+	for _, tt := range tests {
+		parseArgs(t, "--words", path, "openers", "--top", "4", "--bench", "2", "--sort", tt.sort)
+		p := cached.BuildPatternsWith(words, scoring)
+		out := captureStdout(t, func() error { return args.Openers.Run(p) })
+
+		lines := strings.Split(strings.TrimSpace(out), "\n")
+		if len(lines) != 2+4 || !strings.HasPrefix(strings.TrimSpace(lines[1]), "rank  opener") {
+			t.Fatalf("sorted by %s, printed:\n%s", tt.sort, out)
+		}
+		rows := [][]string{}
+		for _, line := range lines[2:] {
+			rows = append(rows, strings.Fields(line))
+		}
+		benched := 0
+		for i, row := range rows {
+			if row[0] != strconv.Itoa(i+1) {
+				t.Errorf("sorted by %s, row %d is ranked %s", tt.sort, i+1, row[0])
+			}
+			if i > 0 && tt.less(row, rows[i-1]) {
+				t.Errorf("sorted by %s, %s ranks below %s", tt.sort, row[1], rows[i-1][1])
+			}
+			if row[5] != "-" {
+				benched++
+			}
+		}
+		if benched != 2 {
+			t.Errorf("sorted by %s, %d openers were benchmarked, want 2", tt.sort, benched)
+		}
+	}
+}
+
+func number(t *testing.T, s string) float64 {
+	t.Helper()
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bit-wordy/src/cached"
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// Replay is the subcommand that re-renders saved games: a file written by --save, or the
+// json lines written by iter and bench with --jsonl, - reads from stdin. A file can also
+// hold bare games, without a solver trace.
+type Replay struct {
+	Files []string `arg:"positional,required"`
+	Trace bool     `arg:"-t,--trace"`
+}
+
+// Run is the implementation of Replay
+func (r Replay) Run() error {
+	for _, path := range r.Files {
+		replays, err := readReplayFile(path)
+		if err != nil {
+			return err
+		}
+		for _, replay := range replays {
+			if !r.Trace {
+				replay.Trace = nil
+			}
+			fmt.Print(replay)
+		}
+	}
+	return nil
+}
+
+// readReplayFile reads the replays in a file, - reads them from stdin
+func readReplayFile(path string) (replays []cached.Replay, err error) {
+	in := os.Stdin
+	if path != "-" {
+		if in, err = os.Open(path); err != nil {
+			return nil, err
+		}
+		defer in.Close()
+	}
+	if replays, err = readReplays(in); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return replays, nil
+}
+
+// readReplays decodes a stream of json values, either replays or bare games
+func readReplays(in io.Reader) ([]cached.Replay, error) {
+	replays := []cached.Replay{}
+	decoder := json.NewDecoder(bufio.NewReader(in))
+	for {
+		var raw json.RawMessage
+		err := decoder.Decode(&raw)
+		if err == io.EOF {
+			return replays, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		replay := cached.Replay{}
+		if err = json.Unmarshal(raw, &replay); err != nil {
+			return nil, err
+		}
+		if replay.Game == nil {
+			// a bare game
+			if err = json.Unmarshal(raw, &replay.Game); err != nil {
+				return nil, err
+			}
+		}
+		if replay.Game.Answer.String() == "" {
+			return nil, fmt.Errorf("%s is not a game", strings.TrimSpace(string(raw)))
+		}
+		replays = append(replays, replay)
+	}
+}
+
+// replayLog writes a replay of every game played as a line of json, a nil log writes nothing
+type replayLog struct {
+	file    *os.File
+	encoder *json.Encoder
+}
+
+// openReplayLog creates the log, returning a nil log when the path is empty
+func openReplayLog(path string) (*replayLog, error) {
+	if path == "" {
+		return nil, nil
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	return &replayLog{file: file, encoder: json.NewEncoder(file)}, nil
+}
+
+// Write logs the replay, the scoring in use is recorded with it
+func (l *replayLog) Write(replay cached.Replay) error {
+	if l == nil {
+		return nil
+	}
+	replay.Scoring = scoring.Name
+	return l.encoder.Encode(replay)
+}
+
+// Close closes the log file
+func (l *replayLog) Close() error {
+	if l == nil {
+		return nil
+	}
+	return l.file.Close()
+}
+
+// saveReplay writes a single game to the file as indented json
+func saveReplay(path string, replay cached.Replay) error {
+	content, err := json.MarshalIndent(replay, "", "\t")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(content, '\n'), 0o644)
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bit-wordy/src/cached"
+	"bit-wordy/src/games"
+	"bit-wordy/src/primitives"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// solvedReplays solves each answer of a small dictionary, recording its replay
+func solvedReplays(t *testing.T, answers ...string) []cached.Replay {
+	t.Helper()
+	dict := primitives.Dictionary{}
+	for _, w := range []string{"tares", "light", "might", "night", "sight", "crane", "batch", "match"} {
+		dict = append(dict, primitives.MakeWord(w))
+	}
+	solver := cached.NewSolver(cached.BuildPatterns(dict))
+	replays := []cached.Replay{}
+	for _, answer := range answers {
+		solver.Reset()
+		g, d, err := solver.Solve(games.NewGame(primitives.MakeWord(answer)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		replays = append(replays, solver.Replay(g, d))
+	}
+	return replays
+}
+
+func TestSaveReplay(t *testing.T) {
+	replay := solvedReplays(t, "night")[0]
+	replay.Scoring, replay.Duration = scoring.Name, time.Millisecond
+	path := filepath.Join(t.TempDir(), "game.json")
+	if err := saveReplay(path, replay); err != nil {
+		t.Fatal(err)
+	}
+	replays, err := readReplayFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(replays) != 1 || !reflect.DeepEqual(replays[0], replay) {
+		t.Errorf("read back %+v, want %+v", replays, replay)
+	}
+}
+
+func TestReplayLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "games.jsonl")
+	replays := solvedReplays(t, "light", "batch", "crane")
+	log, err := openReplayLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, replay := range replays {
+		if err = log.Write(replay); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = log.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	read, err := readReplayFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(read) != len(replays) {
+		t.Fatalf("read %d replays, wrote %d", len(read), len(replays))
+	}
+	for i, replay := range replays {
+		// the log records the scoring in use
+		replay.Scoring = scoring.Name
+		if !reflect.DeepEqual(read[i], replay) {
+			t.Errorf("line %d read back as %+v, want %+v", i+1, read[i], replay)
+		}
+	}
+
+	// without a path nothing is logged
+	if log, err = openReplayLog(""); err != nil || log.Write(replays[0]) != nil || log.Close() != nil {
+		t.Errorf("the empty log failed: %v", err)
+	}
+}
+
+func TestReadReplays(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	bare := write("bare.json", `{"answer":"light","results":[{"word":"tares","pattern":"...y."},{"word":"light","pattern":"ggggg"}]}`)
+	replays, err := readReplayFile(bare)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(replays) != 1 || replays[0].Game.Answer.String() != "light" || len(replays[0].Game.Results) != 2 || replays[0].Trace != nil {
+		t.Errorf("read the bare game as %+v", replays)
+	}
+
+	valid := `{"game":{"answer":"light","results":[]},"won":false}` + "\n"
+	for name, content := range map[string]string{
+		"truncated.jsonl":  valid + `{"game":{"answer":"li`,
+		"corrupt.jsonl":    valid + "not json\n",
+		"not_a_game.json":  valid + `{"played":3}` + "\n",
+		"bad_pattern.json": `{"answer":"light","results":[{"word":"tares","pattern":"...q."}]}`,
+	} {
+		path := write(name, content)
+		if replays, err := readReplayFile(path); err == nil {
+			t.Errorf("%s read as %+v", name, replays)
+		} else if !strings.HasPrefix(err.Error(), path+": ") {
+			t.Errorf("%s: the error %q doesn't name the file", name, err)
+		}
+	}
+
+	if _, err := readReplayFile(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("read a missing file")
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bit-wordy/src/cached"
+	"bit-wordy/src/games"
+	"bit-wordy/src/primitives"
+	"fmt"
+	"io/ioutil"
+	"os"
+)
+
+// Reverse is the subcommand that infers the possible answers from pasted share grids. Each
+// file holds one grid, - reads one from stdin, and all the grids are assumed to be from the
+// same puzzle, so an answer has to be consistent with every one of them.
+type Reverse struct {
+	Grids []string `arg:"positional,required"`
+	Top   int      `arg:"--top" default:"20"`
+}
+
+// Run is the implementation of Reverse
+func (r Reverse) Run(p *cached.Patterns) (err error) {
+	if p == nil {
+		if p, err = loadPatterns(); err != nil {
+			return err
+		}
+	}
+
+	grids := [][]primitives.Pattern{}
+	for _, path := range r.Grids {
+		var content []byte
+		if path == "-" {
+			content, err = ioutil.ReadAll(os.Stdin)
+		} else {
+			content, err = ioutil.ReadFile(path)
+		}
+		if err != nil {
+			return err
+		}
+		share, err := games.ParseShare(string(content))
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		grids = append(grids, share.Patterns)
+	}
+
+	matches, err := p.ReverseSolve(grids...)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%d of %d answers are consistent with the grids\n", len(matches), len(p.Vocab))
+	for rank, match := range matches {
+		if rank >= r.Top {
+			break
+		}
+		fmt.Printf("%4d  %s\n", rank+1, match)
+	}
+
+	return nil
+}
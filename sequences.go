@@ -0,0 +1,55 @@
+package main
+
+import (
+	"bit-wordy/src/cached"
+	"bit-wordy/src/primitives"
+	"fmt"
+	"strings"
+)
+
+// Sequences is the subcommand that finds the best fixed opening sequences of Length words,
+// played whatever the patterns, ranked by joint entropy or, with --sort minimax, by the size
+// of the largest group of answers they leave. --first fixes the opening words, separated by
+// commas, and --pool and --beam trade the breadth of the search against its speed.
+type Sequences struct {
+	Length int    `arg:"positional" default:"2"`
+	Top    int    `arg:"--top" default:"20"`
+	Pool   int    `arg:"--pool" default:"300"`
+	Beam   int    `arg:"--beam" default:"100"`
+	Sort   string `arg:"--sort" default:"entropy"`
+	First  string `arg:"--first"`
+}
+
+// Run is the implementation of Sequences
+func (s Sequences) Run(p *cached.Patterns) (err error) {
+	less, ok := cached.SequenceOrders[s.Sort]
+	if !ok {
+		return fmt.Errorf("unknown sort %q, expected entropy or minimax", s.Sort)
+	}
+	if p == nil {
+		if p, err = loadPatterns(); err != nil {
+			return err
+		}
+	}
+
+	prefix := []primitives.Word{}
+	if s.First != "" {
+		for _, word := range strings.Split(s.First, ",") {
+			prefix = append(prefix, primitives.MakeWord(strings.TrimSpace(word)))
+		}
+	}
+
+	sequences, err := p.BestSequences(s.Length, s.Pool, s.Beam, prefix, less)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("best %d word sequences over %d answers, by %s:\n", s.Length, len(p.Vocab), s.Sort)
+	for rank, seq := range sequences {
+		if rank >= s.Top {
+			break
+		}
+		fmt.Printf("%4d  %s\n", rank+1, seq)
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bit-wordy/src/cached"
+	"bit-wordy/src/server"
+	"bit-wordy/src/sessions"
+	"log"
+	"net/http"
+	"time"
+)
+
+// Serve is the subcommand that runs the solver as an HTTP JSON API, see the server package
+// for the endpoints. The patterns are loaded once at startup and shared by every request.
+// Game sessions are kept in the --sessions file, an empty path turns them off.
+type Serve struct {
+	Addr     string        `arg:"--addr" default:":8080"`
+	Sessions string        `arg:"--sessions" default:"data/sessions.json"`
+	TTL      time.Duration `arg:"--ttl" default:"168h"`
+}
+
+// Run is the implementation of Serve, it only returns when the server fails
+func (s Serve) Run(p *cached.Patterns) (err error) {
+	if p == nil {
+		if p, err = loadPatterns(); err != nil {
+			return err
+		}
+	}
+
+	solver, err := newSolver(p)
+	if err != nil {
+		return err
+	}
+	srv := server.New(p, scoring, strategy)
+	srv.Opener = solver.Opener
+
+	if s.Sessions != "" {
+		m, err := sessions.NewManager(sessions.FileStore{Path: s.Sessions}, p, scoring, strategy)
+		if err != nil {
+			return err
+		}
+		m.TTL, m.Opener = s.TTL, solver.Opener
+		go expireSessions(m)
+		srv.HandleSessions(m)
+		log.Printf("Sessions are kept in %s", s.Sessions)
+	}
+
+	log.Printf("Serving on %s", s.Addr)
+	return http.ListenAndServe(s.Addr, srv)
+}
+
+// expireSessions sweeps the expired sessions out of the store every hour
+func expireSessions(m *sessions.Manager) {
+	for range time.Tick(time.Hour) {
+		n, err := m.Expire()
+		if err != nil {
+			log.Printf("expiring sessions: %s", err)
+		} else if n > 0 {
+			log.Printf("expired %d sessions", n)
+		}
+	}
+}
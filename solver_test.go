@@ -11,7 +11,8 @@ import (
 // }
 
 func BenchmarkIterate_Run(b *testing.B) {
-	iter := Iterate{b.N, false}
+	parseArgs(b)
+	iter := Iterate{Times: b.N, Print: false}
 	err := iter.Run(nil)
 	if err != nil {
 		b.Errorf("%s", err)
@@ -0,0 +1,215 @@
+// Package answers picks the answers of the games a solver plays. Every source is
+// deterministic given its seed, so a run can be repeated exactly.
+package answers
+
+import (
+	"bit-wordy/src/primitives"
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// Source gives the answer of each game in turn
+type Source interface {
+	Next() primitives.Word
+}
+
+// Func adapts a function to a Source, e.g. priors.Sampler.Sample
+type Func func() primitives.Word
+
+// Next calls the function
+func (f Func) Next() primitives.Word {
+	return f()
+}
+
+// Random picks uniformly from the dictionary, with replacement
+type Random struct {
+	dict primitives.Dictionary
+	rng  *rand.Rand
+}
+
+// NewRandom returns a Random source seeded with seed
+func NewRandom(dict primitives.Dictionary, seed int64) *Random {
+	return &Random{dict: dict, rng: rand.New(rand.NewSource(seed))}
+}
+
+// Next returns a random word
+func (r *Random) Next() primitives.Word {
+	return r.dict[r.rng.Intn(len(r.dict))]
+}
+
+// Sequential sweeps through the words in order, starting again from the first at the end
+type Sequential struct {
+	dict primitives.Dictionary
+	next int
+}
+
+// NewSequential returns a Sequential source starting at the first word
+func NewSequential(dict primitives.Dictionary) *Sequential {
+	return &Sequential{dict: dict}
+}
+
+// Next returns the next word in order
+func (s *Sequential) Next() primitives.Word {
+	w := s.dict[s.next]
+	s.next = (s.next + 1) % len(s.dict)
+	return w
+}
+
+// Shuffled goes through the words in a random order without repeating any, then reshuffles
+type Shuffled struct {
+	dict primitives.Dictionary
+	rng  *rand.Rand
+	next int
+}
+
+// NewShuffled returns a Shuffled source seeded with seed, the dictionary is copied
+func NewShuffled(dict primitives.Dictionary, seed int64) *Shuffled {
+	s := &Shuffled{dict: append(primitives.Dictionary{}, dict...), rng: rand.New(rand.NewSource(seed))}
+	s.shuffle()
+	return s
+}
+
+func (s *Shuffled) shuffle() {
+	s.rng.Shuffle(len(s.dict), func(i, j int) { s.dict[i], s.dict[j] = s.dict[j], s.dict[i] })
+	s.next = 0
+}
+
+// Next returns the next word of the shuffle
+func (s *Shuffled) Next() primitives.Word {
+	if s.next == len(s.dict) {
+		s.shuffle()
+	}
+	w := s.dict[s.next]
+	s.next++
+	return w
+}
+
+// NewList returns a source cycling through a fixed list of answers, which must all be in
+// the dictionary
+func NewList(list, dict primitives.Dictionary) (*Sequential, error) {
+	if len(list) == 0 {
+		return nil, fmt.Errorf("the list of answers is empty")
+	}
+	known := map[primitives.Word]bool{}
+	for _, w := range dict {
+		known[w] = true
+	}
+	for _, w := range list {
+		if !known[w] {
+			return nil, fmt.Errorf("answer %s is not in the vocabulary", w)
+		}
+	}
+	return NewSequential(list), nil
+}
+
+// FirstDay is the date of the first daily puzzle, number 0
+var FirstDay = time.Date(2021, time.June, 19, 0, 0, 0, 0, time.UTC)
+
+// DailySeed shuffles the daily answers, it is fixed so every run agrees on the day's answer
+const DailySeed = 0
+
+// DayNumber is the puzzle number of the date, the number of days since FirstDay
+func DayNumber(date time.Time) int {
+	return daysSince(FirstDay, date)
+}
+
+// daysSince is the number of whole days from the epoch to the date, by the calendar
+func daysSince(epoch, date time.Time) int {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	epoch = time.Date(epoch.Year(), epoch.Month(), epoch.Day(), 0, 0, 0, 0, time.UTC)
+	return int(math.Floor(day.Sub(epoch).Hours() / 24))
+}
+
+// Daily derives the answer from the date: day n after the Epoch gets the nth word of the
+// order, starting again at the end. Each call to Next moves on a day.
+type Daily struct {
+	// Epoch is the date of puzzle number 0, FirstDay unless changed
+	Epoch time.Time
+	order primitives.Dictionary
+	day   time.Time
+}
+
+// NewDaily returns a Daily source starting on the date, the dictionary is shuffled once with
+// the seed so no answer repeats until every word has been used
+func NewDaily(dict primitives.Dictionary, seed int64, date time.Time) *Daily {
+	order := append(primitives.Dictionary{}, dict...)
+	rand.New(rand.NewSource(seed)).Shuffle(len(order), func(i, j int) { order[i], order[j] = order[j], order[i] })
+	return NewDailyList(order, date)
+}
+
+// NewDailyList returns a Daily source starting on the date that takes the answers in the
+// order of the list
+func NewDailyList(list primitives.Dictionary, date time.Time) *Daily {
+	return &Daily{Epoch: FirstDay, order: list, day: date}
+}
+
+// Number is the puzzle number of the date, the number of days since the Epoch
+func (d *Daily) Number(date time.Time) int {
+	return daysSince(d.Epoch, date)
+}
+
+// On returns the answer of the date
+func (d *Daily) On(date time.Time) primitives.Word {
+	n := d.Number(date) % len(d.order)
+	if n < 0 {
+		n += len(d.order)
+	}
+	return d.order[n]
+}
+
+// Next returns the answer of the current day and moves on to the next
+func (d *Daily) Next() primitives.Word {
+	w := d.On(d.day)
+	d.day = d.day.AddDate(0, 0, 1)
+	return w
+}
+
+// Parse makes a source from its description:
+//
+//	random             uniformly random words, the default
+//	sequential         every word in dictionary order
+//	shuffled           every word in a random order
+//	daily              the daily answer, starting today
+//	daily:2022-01-31   the daily answer, starting on the date
+//	file:answers.txt   the words in the file, in order
+//	light              the same answer every game
+//
+// The random orders are derived from the seed, except the daily one which is always shuffled
+// with the DailySeed.
+func Parse(spec string, dict primitives.Dictionary, alphabet *primitives.Alphabet, seed int64) (Source, error) {
+	if len(dict) == 0 {
+		return nil, fmt.Errorf("no words to choose answers from")
+	}
+	mode, arg, _ := strings.Cut(spec, ":")
+	switch mode {
+	case "", "random":
+		return NewRandom(dict, seed), nil
+	case "sequential":
+		return NewSequential(dict), nil
+	case "shuffled":
+		return NewShuffled(dict, seed), nil
+	case "daily":
+		date := time.Now()
+		if arg != "" {
+			var err error
+			if date, err = time.Parse("2006-01-02", arg); err != nil {
+				return nil, fmt.Errorf("daily date: %w", err)
+			}
+		}
+		return NewDaily(dict, DailySeed, date), nil
+	case "file":
+		list, err := primitives.LoadWordsFrom(arg, alphabet)
+		if err != nil {
+			return nil, err
+		}
+		return NewList(list, dict)
+	case "word":
+		// for an answer that is also the name of a mode
+		return NewList(primitives.Dictionary{primitives.MakeWord(arg)}, dict)
+	default:
+		return NewList(primitives.Dictionary{primitives.MakeWord(spec)}, dict)
+	}
+}
@@ -0,0 +1,157 @@
+package answers
+
+import (
+	"bit-wordy/src/primitives"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var testDict = func() primitives.Dictionary {
+	dict := primitives.Dictionary{}
+	for _, w := range []string{"tares", "light", "might", "night", "sight", "match", "batch", "crane", "pious", "zesty"} {
+		dict = append(dict, primitives.MakeWord(w))
+	}
+	return dict
+}()
+
+// take draws n answers from the source
+func take(s Source, n int) primitives.Dictionary {
+	words := make(primitives.Dictionary, n)
+	for i := range words {
+		words[i] = s.Next()
+	}
+	return words
+}
+
+func equal(a, b primitives.Dictionary) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestSources_Reproducible(t *testing.T) {
+	date := time.Date(2022, time.March, 1, 0, 0, 0, 0, time.UTC)
+	sources := map[string]func(seed int64) Source{
+		"random":   func(seed int64) Source { return NewRandom(testDict, seed) },
+		"shuffled": func(seed int64) Source { return NewShuffled(testDict, seed) },
+		"daily":    func(seed int64) Source { return NewDaily(testDict, seed, date) },
+	}
+	for name, source := range sources {
+		t.Run(name, func(t *testing.T) {
+			a, b := take(source(7), 30), take(source(7), 30)
+			if !equal(a, b) {
+				t.Errorf("the same seed gave %v and %v", a, b)
+			}
+			if c := take(source(8), 30); equal(a, c) {
+				t.Errorf("seeds 7 and 8 both gave %v", a)
+			}
+		})
+	}
+}
+
+func TestShuffled_NoRepeats(t *testing.T) {
+	s := NewShuffled(testDict, 1)
+	for round := 0; round < 3; round++ {
+		seen := map[primitives.Word]bool{}
+		for _, w := range take(s, len(testDict)) {
+			if seen[w] {
+				t.Fatalf("round %d repeats %s", round, w)
+			}
+			seen[w] = true
+		}
+	}
+}
+
+func TestSequential_Wraps(t *testing.T) {
+	got := take(NewSequential(testDict), len(testDict)+2)
+	want := append(append(primitives.Dictionary{}, testDict...), testDict[:2]...)
+	if !equal(got, want) {
+		t.Errorf("sequential gave %v", got)
+	}
+}
+
+func TestDaily(t *testing.T) {
+	start := time.Date(2022, time.March, 1, 0, 0, 0, 0, time.UTC)
+	d := NewDailyList(testDict, start)
+	d.Epoch = start.AddDate(0, 0, -2)
+
+	if n := d.Number(start.Add(23 * time.Hour)); n != 2 {
+		t.Errorf("puzzle number %d late in the day, want 2", n)
+	}
+	if got := d.On(start); got != testDict[2] {
+		t.Errorf("answer on day 2 is %s, want %s", got, testDict[2])
+	}
+	// before the epoch the answers count backwards through the list
+	if got := d.On(d.Epoch.AddDate(0, 0, -1)); got != testDict[len(testDict)-1] {
+		t.Errorf("answer the day before the epoch is %s", got)
+	}
+	if got := take(d, 3); !equal(got, testDict[2:5]) {
+		t.Errorf("the next three days are %v", got)
+	}
+	if n := DayNumber(FirstDay.AddDate(0, 0, 365)); n != 365 {
+		t.Errorf("a year after the first day is puzzle %d", n)
+	}
+}
+
+func TestParse(t *testing.T) {
+	dir := t.TempDir()
+	answers, outside := filepath.Join(dir, "answers"), filepath.Join(dir, "outside")
+	if err := os.WriteFile(answers, []byte("night\nlight\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(outside, []byte("night\nquack\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		spec    string
+		want    primitives.Dictionary
+		wantErr bool
+	}{
+		{spec: "sequential", want: testDict[:3]},
+		{spec: "file:" + answers, want: primitives.Dictionary{testDict[3], testDict[1], testDict[3]}},
+		{spec: "file:" + outside, wantErr: true},
+		{spec: "file:" + filepath.Join(dir, "missing"), wantErr: true},
+		{spec: "light", want: primitives.Dictionary{testDict[1], testDict[1], testDict[1]}},
+		{spec: "word:match", want: primitives.Dictionary{testDict[5], testDict[5], testDict[5]}},
+		{spec: "quack", wantErr: true},
+		{spec: "daily:2022-03-01"},
+		{spec: "daily:01/03/2022", wantErr: true},
+		{spec: "random"},
+		{spec: "shuffled"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			s, err := Parse(tt.spec, testDict, primitives.Latin, 1)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil || tt.want == nil {
+				return
+			}
+			if got := take(s, len(tt.want)); !equal(got, tt.want) {
+				t.Errorf("Parse() gave %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// a daily spec starts on its date, whatever the seed
+	want := take(NewDaily(testDict, DailySeed, time.Date(2022, time.March, 1, 0, 0, 0, 0, time.UTC)), 5)
+	for _, seed := range []int64{1, 2, time.Now().UnixNano()} {
+		s, err := Parse("daily:2022-03-01", testDict, primitives.Latin, seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := take(s, 5); !equal(got, want) {
+			t.Errorf("daily:2022-03-01 with seed %d gave %v, want %v", seed, got, want)
+		}
+	}
+}
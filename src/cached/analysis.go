@@ -0,0 +1,112 @@
+package cached
+
+import (
+	"bit-wordy/src/games"
+	"bit-wordy/src/primitives"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// TurnAnalysis grades a single guess. The embedded outcome holds the guess's expected and
+// actual information and the answers left before and after it, Best is the allowed word with
+// the most information at that point.
+type TurnAnalysis struct {
+	GuessOutcome
+	Best     primitives.Word `json:"best"`
+	BestInfo float64         `json:"best_info"`
+	// Skill is the guess's expected information as a percentage of the best's
+	Skill float64 `json:"skill"`
+	// Luck is the information the guess gave beyond what it was expected to, in bits
+	Luck float64 `json:"luck"`
+}
+
+// Analysis grades every guess of a game, Skill is the average of the turns' and Luck is
+// their total
+type Analysis struct {
+	Answer primitives.Word `json:"answer"`
+	Won    bool            `json:"won"`
+	Turns  []TurnAnalysis  `json:"turns"`
+	Skill  float64         `json:"skill"`
+	Luck   float64         `json:"luck"`
+}
+
+// Analyse replays the game's results through the patterns, comparing each guess with the
+// best one available. The results are taken as they are, so a game played with another
+// scoring or by hand can be analysed as long as the patterns agree with it.
+func (p *Patterns) Analyse(g *games.Game) (Analysis, error) {
+	a := Analysis{Answer: g.Answer, Won: g.IsWon()}
+	current := p
+	for turn, result := range g.Results {
+		info, ok := current.EntropyOf(result.Word)
+		if !ok {
+			return a, fmt.Errorf("turn %d: %s is not in the vocabulary", turn+1, result.Word)
+		}
+		best, bestInfo := current.bestProbe()
+
+		next, err := current.Prune(result)
+		if err != nil {
+			return a, fmt.Errorf("turn %d: %w", turn+1, err)
+		}
+		t := TurnAnalysis{
+			GuessOutcome: MakeOutcome(info, result, current, next),
+			Best:         best,
+			BestInfo:     bestInfo,
+		}
+		switch {
+		case bestInfo > tieTolerance:
+			t.Skill = 100 * info / bestInfo
+		case result.Pattern == primitives.Win:
+			// the answer was known and found
+			t.Skill = 100
+		}
+		if t.Luck = t.ActualInfo - t.ExpectedInfo; math.Abs(t.Luck) < tieTolerance {
+			t.Luck = 0
+		}
+		a.Turns = append(a.Turns, t)
+		a.Skill += t.Skill
+		a.Luck += t.Luck
+		current = next
+	}
+	if len(a.Turns) > 0 {
+		a.Skill /= float64(len(a.Turns))
+	}
+
+	return a, nil
+}
+
+// bestProbe is the allowed word with the greatest entropy against the remaining answers. Ties
+// go to a remaining answer, as it might win outright.
+func (p *Patterns) bestProbe() (best primitives.Word, bestInfo float64) {
+	best, bestInfo = p.GetBestGuess()
+	for _, guess := range p.Root().Vocab {
+		info, _ := p.EntropyOf(guess)
+		if info > bestInfo+tieTolerance {
+			best, bestInfo = guess, info
+		}
+	}
+	return best, bestInfo
+}
+
+func (a Analysis) String() string {
+	lines := []string{fmt.Sprintf(
+		"%-4s  %-5s  %7s  %-5s  %7s  %6s  %6s  %5s  %6s",
+		"turn", "guess", "info", "best", "info", "before", "after", "skill", "luck",
+	)}
+	for turn, t := range a.Turns {
+		lines = append(lines, fmt.Sprintf(
+			"%-4d  %s  %7.2f  %s  %7.2f  %6d  %6d  %5.0f  %+6.2f",
+			turn+1, t.Result, t.ExpectedInfo, t.Best, t.BestInfo, t.Before, t.After, t.Skill, t.Luck,
+		))
+	}
+	outcome := "lost"
+	if a.Won {
+		outcome = "won"
+	}
+	lines = append(lines, fmt.Sprintf(
+		"answer: %s, %s in %d, skill: %.0f/100, luck: %+.2f bits",
+		a.Answer, outcome, len(a.Turns), a.Skill, a.Luck,
+	))
+
+	return strings.Join(lines, "\n") + "\n"
+}
@@ -0,0 +1,76 @@
+package cached
+
+import (
+	"bit-wordy/src/games"
+	"bit-wordy/src/primitives"
+	"math"
+	"testing"
+)
+
+func TestPatterns_Analyse(t *testing.T) {
+	dict := dictionary("tares", "light", "might", "night", "sight", "fight", "match", "batch", "crane", "pious")
+	p := BuildPatterns(dict)
+	g := games.NewGame(primitives.MakeWord("light"))
+	for _, guess := range []string{"tares", "night", "might", "fight", "light"} {
+		g.Guess(primitives.MakeWord(guess))
+	}
+
+	a, err := p.Analyse(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !a.Won || len(a.Turns) != 5 {
+		t.Fatalf("analysed %d turns, won %v", len(a.Turns), a.Won)
+	}
+
+	current, skill, luck := p, 0.0, 0.0
+	for turn, ta := range a.Turns {
+		// the counts are the answers consistent with the results so far
+		next, err := current.Prune(ta.Result)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ta.Before != len(current.Vocab) || ta.After != len(next.Vocab) {
+			t.Errorf("turn %d: %d -> %d answers, want %d -> %d", turn+1, ta.Before, ta.After, len(current.Vocab), len(next.Vocab))
+		}
+		// no allowed word has more information than the best
+		for _, w := range dict {
+			if info, _ := current.EntropyOf(w); info > ta.BestInfo+tieTolerance {
+				t.Errorf("turn %d: %s has %.3f bits, more than the best %s", turn+1, w, info, ta.Best)
+			}
+		}
+		if ta.Skill > 100+tieTolerance || ta.Skill < 0 {
+			t.Errorf("turn %d: skill %.2f", turn+1, ta.Skill)
+		}
+		if math.Abs(ta.Luck-(ta.ActualInfo-ta.ExpectedInfo)) > 1e-6 {
+			t.Errorf("turn %d: luck %.3f isn't the actual less the expected information", turn+1, ta.Luck)
+		}
+		skill, luck = skill+ta.Skill, luck+ta.Luck
+		current = next
+	}
+	if last := a.Turns[4]; last.Skill != 100 || last.Before != 1 {
+		t.Errorf("guessing the only answer left scored %.2f with %d left", last.Skill, last.Before)
+	}
+	if math.Abs(a.Skill-skill/5) > 1e-9 || math.Abs(a.Luck-luck) > 1e-9 {
+		t.Errorf("game skill %.2f and luck %.2f aren't the turns' average and total", a.Skill, a.Luck)
+	}
+}
+
+func TestPatterns_AnalyseErrors(t *testing.T) {
+	p := BuildPatterns(dictionary("tares", "light", "might", "night"))
+	tests := []struct {
+		name    string
+		results primitives.ResultSet
+	}{
+		{"not in the vocabulary", primitives.ResultSet{{Word: primitives.MakeWord("zzzzz"), Pattern: primitives.Win}}},
+		{"no answer gives the pattern", primitives.ResultSet{{Word: primitives.MakeWord("tares"), Pattern: primitives.Win}, {Word: primitives.MakeWord("light"), Pattern: primitives.Win}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &games.Game{Answer: primitives.MakeWord("light"), Results: tt.results}
+			if _, err := p.Analyse(g); err == nil {
+				t.Error("analysed an impossible game")
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package cached
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+)
+
+// Curve is the fitted expected number of guesses needed to finish a game when some
+// uncertainty remains, including the guess about to be made:
+//
+//	f(H) = A + B·log2(1 + H)
+//
+// The default is a rough fit to classic wordle games, benchmark runs fit a better one.
+type Curve struct {
+	A, B float64
+	// R2 and Samples describe the quality of the fit, they are zero for the default
+	R2      float64
+	Samples int
+}
+
+// DefaultCurve is used until a curve has been fitted for the dictionary being played
+var DefaultCurve = Curve{A: 1, B: 0.6}
+
+// Eval is f(H)
+func (c Curve) Eval(uncertainty float64) float64 {
+	return c.A + c.B*math.Log2(1+uncertainty)
+}
+
+func (c Curve) String() string {
+	return fmt.Sprintf("f(H) = %.4f + %.4f·log2(1 + H)  (R² = %.4f over %d samples)", c.A, c.B, c.R2, c.Samples)
+}
+
+// CurveSample is an observation of how many guesses it took to finish a game from a turn with
+// the given uncertainty, that guess included
+type CurveSample struct {
+	Uncertainty float64
+	Guesses     int
+}
+
+// FitCurve is the least squares fit of the Curve to the samples
+func FitCurve(samples []CurveSample) (Curve, error) {
+	n := float64(len(samples))
+	if n < 2 {
+		return Curve{}, fmt.Errorf("need at least 2 samples to fit a curve, got %d", len(samples))
+	}
+
+	var sx, sy, sxx, sxy float64
+	for _, s := range samples {
+		x, y := math.Log2(1+s.Uncertainty), float64(s.Guesses)
+		sx, sy, sxx, sxy = sx+x, sy+y, sxx+x*x, sxy+x*y
+	}
+	denominator := n*sxx - sx*sx
+	if denominator == 0 {
+		return Curve{}, fmt.Errorf("samples all have the same uncertainty, can't fit a curve")
+	}
+	c := Curve{Samples: len(samples)}
+	c.B = (n*sxy - sx*sy) / denominator
+	c.A = (sy - c.B*sx) / n
+
+	mean, ssTot, ssRes := sy/n, 0.0, 0.0
+	for _, s := range samples {
+		y := float64(s.Guesses)
+		ssTot += (y - mean) * (y - mean)
+		ssRes += (y - c.Eval(s.Uncertainty)) * (y - c.Eval(s.Uncertainty))
+	}
+	if ssTot > 0 {
+		c.R2 = 1 - ssRes/ssTot
+	}
+
+	return c, nil
+}
+
+// LoadCurve reads a curve saved by Curve.Save
+func LoadCurve(path string) (Curve, error) {
+	c := Curve{}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return c, err
+	}
+	err = json.Unmarshal(content, &c)
+	return c, err
+}
+
+// Save writes the curve as json
+func (c Curve) Save(path string) error {
+	content, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, content, 0o644)
+}
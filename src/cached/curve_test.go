@@ -0,0 +1,56 @@
+package cached
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// TestFitCurve_Exact fits samples lying on a curve, the uncertainties are 2^k - 1 so that
+// the curve gives a whole number of guesses
+func TestFitCurve_Exact(t *testing.T) {
+	want := Curve{A: 1, B: 2}
+	samples := []CurveSample{}
+	for k := 0; k <= 10; k++ {
+		h := math.Pow(2, float64(k)) - 1
+		samples = append(samples, CurveSample{Uncertainty: h, Guesses: int(want.Eval(h))})
+	}
+	got, err := FitCurve(samples)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(got.A-want.A) > 1e-9 || math.Abs(got.B-want.B) > 1e-9 || math.Abs(got.R2-1) > 1e-9 {
+		t.Errorf("fitted %s, want A=%.4f B=%.4f", got, want.A, want.B)
+	}
+}
+
+// TestFitCurve_Noisy recovers a wordle-like curve from noisy games, rounded to whole guesses
+func TestFitCurve_Noisy(t *testing.T) {
+	want := Curve{A: 1.3, B: 0.45}
+	rng := rand.New(rand.NewSource(1))
+	samples := make([]CurveSample, 20000)
+	for i := range samples {
+		h := rng.Float64() * 11
+		samples[i] = CurveSample{Uncertainty: h, Guesses: int(math.Round(want.Eval(h) + rng.NormFloat64()*0.5))}
+	}
+	got, err := FitCurve(samples)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(got.A-want.A) > 0.05 || math.Abs(got.B-want.B) > 0.02 {
+		t.Errorf("fitted %s, want A=%.4f B=%.4f", got, want.A, want.B)
+	}
+	if got.Samples != len(samples) || got.R2 <= 0 || got.R2 >= 1 {
+		t.Errorf("fit quality R²=%.4f over %d samples", got.R2, got.Samples)
+	}
+}
+
+func TestFitCurve_Degenerate(t *testing.T) {
+	if _, err := FitCurve([]CurveSample{{Uncertainty: 3, Guesses: 2}}); err == nil {
+		t.Error("fitted a single sample")
+	}
+	same := []CurveSample{{Uncertainty: 3, Guesses: 2}, {Uncertainty: 3, Guesses: 4}}
+	if _, err := FitCurve(same); err == nil {
+		t.Error("fitted samples with the same uncertainty")
+	}
+}
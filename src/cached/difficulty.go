@@ -0,0 +1,152 @@
+package cached
+
+import (
+	"bit-wordy/src/games"
+	"bit-wordy/src/primitives"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Difficulty is how hard an answer is for a solver: the guesses it took, whether it was lost,
+// the number of answers (itself included) sharing its pattern after the opener and the number
+// of answers that differ from it in a single position
+type Difficulty struct {
+	Answer       primitives.Word `json:"answer"`
+	Guesses      int             `json:"guesses"`
+	Lost         bool            `json:"lost"`
+	OpenerBucket int             `json:"opener_bucket"`
+	Neighbours   int             `json:"neighbours"`
+}
+
+func (d Difficulty) String() string {
+	guesses := fmt.Sprint(d.Guesses)
+	if d.Lost {
+		guesses = "X"
+	}
+	return fmt.Sprintf("%s  %7s  %13d  %10d", d.Answer, guesses, d.OpenerBucket, d.Neighbours)
+}
+
+// harder orders the answers lost first, then by guesses, neighbours and opener bucket
+func harder(a, b Difficulty) bool {
+	switch {
+	case a.Lost != b.Lost:
+		return a.Lost
+	case a.Guesses != b.Guesses:
+		return a.Guesses > b.Guesses
+	case a.Neighbours != b.Neighbours:
+		return a.Neighbours > b.Neighbours
+	default:
+		return a.OpenerBucket > b.OpenerBucket
+	}
+}
+
+// template blanks out the letter at position i, e.g. _ight for light at 0
+func template(w primitives.Word, i int) string {
+	w[i] = '_'
+	return w.String()
+}
+
+// Difficulties solves every answer of the patterns with the solver and scoring, returning
+// them hardest first
+func (f *FastSolver) Difficulties(scoring primitives.Scoring) ([]Difficulty, error) {
+	p := f.Initial
+	families := map[string]int{}
+	for _, w := range p.Vocab {
+		for i := range w {
+			families[template(w, i)]++
+		}
+	}
+	buckets := map[byte]int{}
+	row, _ := p.GuessRow(f.Opener)
+	for _, pattern := range row {
+		buckets[pattern]++
+	}
+
+	difficulties := make([]Difficulty, len(p.Vocab))
+	for ansId, answer := range p.Vocab {
+		f.Reset()
+		g, _, err := f.Solve(games.NewGameWith(answer, scoring))
+		if err != nil {
+			return nil, fmt.Errorf("solving %s: %w", answer, err)
+		}
+		d := Difficulty{
+			Answer:       answer,
+			Guesses:      len(g.Results),
+			Lost:         g.IsLost(),
+			OpenerBucket: buckets[row[ansId]],
+		}
+		for i := range answer {
+			d.Neighbours += families[template(answer, i)] - 1
+		}
+		difficulties[ansId] = d
+	}
+	sort.SliceStable(difficulties, func(i, j int) bool { return harder(difficulties[i], difficulties[j]) })
+
+	return difficulties, nil
+}
+
+// Cluster is a family of answers differing only at one position, such as _ight or s_ore
+type Cluster struct {
+	Template string       `json:"template"`
+	Members  []Difficulty `json:"members"`
+	Lost     int          `json:"lost"`
+	Mean     float64      `json:"mean"`
+}
+
+func (c Cluster) String() string {
+	members := make([]string, len(c.Members))
+	for i, m := range c.Members {
+		members[i] = m.Answer.String()
+	}
+	return fmt.Sprintf(
+		"%-6s  %4d  %4d  %5.2f  %s",
+		c.Template, len(c.Members), c.Lost, c.Mean, strings.Join(members, " "),
+	)
+}
+
+// Clusters groups the answers into families of at least minSize, worst first: those with
+// the most losses, then the most guesses on average. Lost games count as MaxGuesses+1
+// guesses in the mean. An answer is in a family for each position it shares with others.
+func Clusters(difficulties []Difficulty, minSize int) []Cluster {
+	families := map[string]*Cluster{}
+	for _, d := range difficulties {
+		for i := range d.Answer {
+			t := template(d.Answer, i)
+			if families[t] == nil {
+				families[t] = &Cluster{Template: t}
+			}
+			c := families[t]
+			c.Members = append(c.Members, d)
+			guesses := d.Guesses
+			if d.Lost {
+				c.Lost++
+				guesses = games.MaxGuesses + 1
+			}
+			c.Mean += float64(guesses)
+		}
+	}
+
+	clusters := []Cluster{}
+	for _, c := range families {
+		if len(c.Members) < minSize {
+			continue
+		}
+		c.Mean /= float64(len(c.Members))
+		sort.SliceStable(c.Members, func(i, j int) bool { return harder(c.Members[i], c.Members[j]) })
+		clusters = append(clusters, *c)
+	}
+	sort.Slice(clusters, func(i, j int) bool {
+		a, b := clusters[i], clusters[j]
+		switch {
+		case a.Lost != b.Lost:
+			return a.Lost > b.Lost
+		case a.Mean != b.Mean:
+			return a.Mean > b.Mean
+		default:
+			return a.Template < b.Template
+		}
+	})
+
+	return clusters
+}
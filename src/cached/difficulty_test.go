@@ -0,0 +1,104 @@
+package cached
+
+import (
+	"bit-wordy/src/games"
+	"bit-wordy/src/primitives"
+	"math"
+	"testing"
+)
+
+func TestFastSolver_Difficulties(t *testing.T) {
+	dict := dictionary(
+		"tares", "light", "might", "night", "sight", "fight", "tight", "right",
+		"match", "batch", "crane", "pious", "zesty", "dough", "wacky",
+	)
+	p := BuildPatterns(dict)
+	solver := NewSolver(p)
+	ranking, err := solver.Difficulties(primitives.Classic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ranking) != len(dict) {
+		t.Fatalf("ranked %d of %d answers", len(ranking), len(dict))
+	}
+
+	for i, d := range ranking {
+		if i > 0 && harder(d, ranking[i-1]) {
+			t.Errorf("%s ranks below %s but is harder", d.Answer, ranking[i-1].Answer)
+		}
+
+		solver.Reset()
+		g, _, err := solver.Solve(games.NewGame(d.Answer))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d.Guesses != len(g.Results) || d.Lost != g.IsLost() {
+			t.Errorf("%s took %d guesses, lost %v, solving it again took %d", d.Answer, d.Guesses, d.Lost, len(g.Results))
+		}
+
+		bucket, neighbours := 0, 0
+		for _, w := range dict {
+			if w.CheckGuess(DefaultOpener) == d.Answer.CheckGuess(DefaultOpener) {
+				bucket++
+			}
+			differ := 0
+			for j := range w {
+				if w[j] != d.Answer[j] {
+					differ++
+				}
+			}
+			if differ == 1 {
+				neighbours++
+			}
+		}
+		if d.OpenerBucket != bucket || d.Neighbours != neighbours {
+			t.Errorf("%s has opener bucket %d and %d neighbours, want %d and %d", d.Answer, d.OpenerBucket, d.Neighbours, bucket, neighbours)
+		}
+	}
+}
+
+func TestClusters(t *testing.T) {
+	difficulty := func(w string, guesses int, lost bool) Difficulty {
+		return Difficulty{Answer: primitives.MakeWord(w), Guesses: guesses, Lost: lost}
+	}
+	ds := []Difficulty{
+		difficulty("light", 3, false),
+		difficulty("might", 6, true),
+		difficulty("night", 4, false),
+		difficulty("match", 5, false),
+		difficulty("batch", 4, false),
+		difficulty("catch", 5, false),
+		difficulty("mitch", 2, false),
+	}
+
+	clusters := Clusters(ds, 3)
+	want := []struct {
+		template string
+		members  []string
+		lost     int
+		mean     float64
+	}{
+		{"_ight", []string{"might", "night", "light"}, 1, (3 + 4 + float64(games.MaxGuesses+1)) / 3},
+		{"_atch", []string{"match", "catch", "batch"}, 0, (5 + 4 + 5) / 3.0},
+	}
+	if len(clusters) != len(want) {
+		t.Fatalf("found clusters %v", clusters)
+	}
+	for i, c := range clusters {
+		w := want[i]
+		if c.Template != w.template || c.Lost != w.lost || math.Abs(c.Mean-w.mean) > 1e-9 {
+			t.Errorf("cluster %d is %s, want %s with %d lost and mean %.2f", i, c, w.template, w.lost, w.mean)
+		}
+		for j, m := range c.Members {
+			if j >= len(w.members) || m.Answer.String() != w.members[j] {
+				t.Errorf("cluster %s members %s", c.Template, c)
+				break
+			}
+		}
+	}
+
+	// mitch joins match in m_tch, a family of two
+	if pairs := Clusters(ds, 2); len(pairs) != 3 || pairs[2].Template != "m_tch" {
+		t.Errorf("clusters of two or more: %v", pairs)
+	}
+}
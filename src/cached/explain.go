@@ -0,0 +1,261 @@
+package cached
+
+import (
+	"bit-wordy/src/games"
+	"bit-wordy/src/primitives"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Candidate is a guess as it looked before it was made: its entropy against the remaining
+// answers, the size of the largest group of answers it could leave and the probability that
+// it is the answer itself
+type Candidate struct {
+	Word        primitives.Word `json:"word"`
+	Entropy     float64         `json:"entropy"`
+	WorstBucket int             `json:"worst_bucket"`
+	Probability float64         `json:"probability"`
+}
+
+// TurnExplanation is a single turn of a game explained: the best candidates by entropy, the
+// guess made (which is ranked among them whether or not it made the top), a sample of the
+// answers it eliminated and, when there are few enough, the answers it left. The candidates
+// are always ranked by entropy, whichever strategy made the guess.
+type TurnExplanation struct {
+	Result     primitives.Result     `json:"result"`
+	Before     int                   `json:"before"`
+	Candidates []Candidate           `json:"candidates"`
+	Chosen     Candidate             `json:"chosen"`
+	Eliminated int                   `json:"eliminated"`
+	Sample     primitives.Dictionary `json:"sample"`
+	Remaining  primitives.Dictionary `json:"remaining,omitempty"`
+}
+
+// Explanation is every turn of a game explained
+type Explanation struct {
+	Answer primitives.Word   `json:"answer"`
+	Won    bool              `json:"won"`
+	Turns  []TurnExplanation `json:"turns"`
+}
+
+// ExplainOptions sizes an Explanation: Top candidates are listed, Samples eliminated answers
+// are shown and the remaining answers are listed when there are at most Few
+type ExplainOptions struct {
+	Top, Samples, Few int
+}
+
+// DefaultExplainOptions lists the top 5 candidates, 8 eliminated answers and up to 10 left
+var DefaultExplainOptions = ExplainOptions{Top: 5, Samples: 8, Few: 10}
+
+// Explain replays the game's results through the patterns, as Analyse does, recording what
+// the solver could see at each turn
+func (p *Patterns) Explain(g *games.Game, opts ExplainOptions) (Explanation, error) {
+	e := Explanation{Answer: g.Answer, Won: g.IsWon()}
+	current := p
+	for turn, result := range g.Results {
+		chosen, ok := current.candidate(result.Word)
+		if !ok {
+			return e, fmt.Errorf("turn %d: %s is not in the vocabulary", turn+1, result.Word)
+		}
+		next, err := current.Prune(result)
+		if err != nil {
+			return e, fmt.Errorf("turn %d: %w", turn+1, err)
+		}
+
+		t := TurnExplanation{
+			Result:     result,
+			Before:     len(current.Vocab),
+			Candidates: current.topCandidates(opts.Top),
+			Chosen:     chosen,
+			Eliminated: len(current.Vocab) - len(next.Vocab),
+			Sample:     sampleEliminated(current.Vocab, next.Vocab, opts.Samples),
+		}
+		if len(next.Vocab) <= opts.Few {
+			t.Remaining = next.Vocab
+		}
+		e.Turns = append(e.Turns, t)
+		current = next
+	}
+
+	return e, nil
+}
+
+// candidate scores any word of the root vocabulary as a guess against the remaining answers
+func (p *Patterns) candidate(guess primitives.Word) (Candidate, bool) {
+	row, ok := p.GuessRow(guess)
+	if !ok {
+		return Candidate{}, false
+	}
+	c := Candidate{Word: guess}
+	counts := make([]int, len(p.patternIndex))
+	buckets := make([]float64, len(p.patternIndex))
+	total := 0.0
+	for ansId, pattern := range row {
+		counts[pattern]++
+		if counts[pattern] > c.WorstBucket {
+			c.WorstBucket = counts[pattern]
+		}
+		buckets[pattern] += p.Weight(ansId)
+		total += p.Weight(ansId)
+	}
+	for _, weight := range buckets {
+		if weight == 0 {
+			continue
+		}
+		probability := weight / total
+		c.Entropy += -(probability * math.Log2(probability))
+	}
+	if ansId, ok := (*p.index)[guess]; ok {
+		c.Probability = p.Weight(ansId) / total
+	}
+
+	return c, true
+}
+
+// topCandidates is the n allowed words with the greatest entropy, ties going to the one most
+// likely to be the answer
+func (p *Patterns) topCandidates(n int) []Candidate {
+	candidates := []Candidate{}
+	for _, guess := range p.Root().Vocab {
+		c, _ := p.candidate(guess)
+		candidates = append(candidates, c)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if math.Abs(candidates[i].Entropy-candidates[j].Entropy) < tieTolerance {
+			return candidates[i].Probability > candidates[j].Probability
+		}
+		return candidates[i].Entropy > candidates[j].Entropy
+	})
+	if n < len(candidates) {
+		candidates = candidates[:n]
+	}
+	return candidates
+}
+
+// sampleEliminated picks up to n of the answers in before that aren't in after, evenly spaced
+// so that the same game is always explained the same way
+func sampleEliminated(before, after primitives.Dictionary, n int) primitives.Dictionary {
+	left := map[primitives.Word]bool{}
+	for _, w := range after {
+		left[w] = true
+	}
+	eliminated := primitives.Dictionary{}
+	for _, w := range before {
+		if !left[w] {
+			eliminated = append(eliminated, w)
+		}
+	}
+	if n <= 0 || len(eliminated) <= n {
+		return eliminated
+	}
+	sample := make(primitives.Dictionary, n)
+	for i := range sample {
+		sample[i] = eliminated[i*len(eliminated)/n]
+	}
+	return sample
+}
+
+// rows are the candidates of the turn with the chosen guess marked, it is added at the end
+// if it didn't make the top
+func (t TurnExplanation) rows() (rows [][]string) {
+	listed := false
+	row := func(c Candidate) []string {
+		mark := ""
+		if c.Word == t.Chosen.Word {
+			mark, listed = "*", true
+		}
+		return []string{
+			mark + c.Word.String(),
+			fmt.Sprintf("%.2f", c.Entropy),
+			fmt.Sprint(c.WorstBucket),
+			fmt.Sprintf("%.1f%%", 100*c.Probability),
+		}
+	}
+	for _, c := range t.Candidates {
+		rows = append(rows, row(c))
+	}
+	if !listed {
+		rows = append(rows, row(t.Chosen))
+	}
+	return rows
+}
+
+// eliminated is how many answers the guess eliminated, with the sample
+func (t TurnExplanation) eliminated() string {
+	if t.Eliminated == 0 {
+		return "eliminated none"
+	}
+	return fmt.Sprintf("eliminated %d, e.g. %s", t.Eliminated, joinWords(t.Sample, ", "))
+}
+
+func (e Explanation) outcome() string {
+	if e.Won {
+		return fmt.Sprintf("answer: %s, won in %d", e.Answer, len(e.Turns))
+	}
+	return fmt.Sprintf("answer: %s, lost", e.Answer)
+}
+
+// String renders the explanation for the terminal, the guess made is marked with a *
+func (e Explanation) String() string {
+	lines := []string{}
+	for turn, t := range e.Turns {
+		lines = append(lines, fmt.Sprintf("turn %d, answers left: %d, top guesses by entropy", turn+1, t.Before))
+		lines = append(lines, fmt.Sprintf("\t%-7s  %7s  %5s  %6s", "guess", "entropy", "worst", "P(ans)"))
+		for _, r := range t.rows() {
+			lines = append(lines, fmt.Sprintf("\t%-7s  %7s  %5s  %6s", r[0], r[1], r[2], r[3]))
+		}
+		lines = append(lines, fmt.Sprintf("\tpattern: %s %s", t.Result, t.Result.Pattern.Format(primitives.LetterNotation)))
+		lines = append(lines, "\t"+t.eliminated())
+		if t.Remaining != nil {
+			lines = append(lines, fmt.Sprintf("\tremaining: %s", joinWords(t.Remaining, ", ")))
+		}
+	}
+	lines = append(lines, e.outcome())
+
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// Markdown renders the explanation for sharing, with a table per turn and the patterns in
+// the notation, which should be one of the emoji notations
+func (e Explanation) Markdown(n primitives.Notation) string {
+	lines := []string{}
+	for turn, t := range e.Turns {
+		lines = append(lines,
+			fmt.Sprintf("### Turn %d, answers left: %d", turn+1, t.Before),
+			"",
+			"Top guesses by entropy:",
+			"",
+			"| guess | entropy | worst bucket | P(answer) |",
+			"|---|--:|--:|--:|",
+		)
+		for _, r := range t.rows() {
+			if strings.HasPrefix(r[0], "*") {
+				r[0] = "**" + strings.TrimPrefix(r[0], "*") + "**"
+			}
+			lines = append(lines, "| "+strings.Join(r, " | ")+" |")
+		}
+		lines = append(lines,
+			"",
+			fmt.Sprintf("Guessed **%s** %s", t.Result.Word, t.Result.Pattern.Format(n)),
+			"",
+			strings.ToUpper(t.eliminated()[:1])+t.eliminated()[1:],
+		)
+		if t.Remaining != nil {
+			lines = append(lines, "", fmt.Sprintf("Remaining: %s", joinWords(t.Remaining, ", ")))
+		}
+		lines = append(lines, "")
+	}
+	lines = append(lines, fmt.Sprintf("**%s**", e.outcome()))
+
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func joinWords(words primitives.Dictionary, sep string) string {
+	s := make([]string, len(words))
+	for i, w := range words {
+		s[i] = w.String()
+	}
+	return strings.Join(s, sep)
+}
@@ -0,0 +1,134 @@
+package cached
+
+import (
+	"bit-wordy/src/games"
+	"bit-wordy/src/primitives"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// explainedGame is light found by way of the trap dictionary's probe
+func explainedGame(t *testing.T, opts ExplainOptions) (*Patterns, Explanation) {
+	p := BuildPatterns(trapDictionary())
+	g := games.NewGame(primitives.MakeWord("light"))
+	for _, guess := range []string{"tares", "flmns", "light"} {
+		g.Guess(primitives.MakeWord(guess))
+	}
+	e, err := p.Explain(g, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p, e
+}
+
+func TestPatterns_Explain(t *testing.T) {
+	p, e := explainedGame(t, ExplainOptions{Top: 3, Samples: 2, Few: 1})
+	if !e.Won || e.Answer.String() != "light" || len(e.Turns) != 3 {
+		t.Fatalf("explained %s", e.outcome())
+	}
+
+	current := p
+	for i, turn := range e.Turns {
+		if turn.Before != len(current.Vocab) {
+			t.Errorf("turn %d: %d answers before, want %d", i+1, turn.Before, len(current.Vocab))
+		}
+		if len(turn.Candidates) != 3 {
+			t.Errorf("turn %d: %d candidates, want the top 3", i+1, len(turn.Candidates))
+		}
+		for j, c := range turn.Candidates {
+			if j > 0 && c.Entropy > turn.Candidates[j-1].Entropy+tieTolerance {
+				t.Errorf("turn %d: %s ranks below %s with more entropy", i+1, c.Word, turn.Candidates[j-1].Word)
+			}
+		}
+		// any allowed word is a candidate, not only the remaining answers
+		best := 0.0
+		for _, w := range p.Vocab {
+			entropy, _ := current.EntropyOf(w)
+			best = math.Max(best, entropy)
+		}
+		if math.Abs(turn.Candidates[0].Entropy-best) > 1e-9 {
+			t.Errorf("turn %d: top candidate has entropy %f, want %f", i+1, turn.Candidates[0].Entropy, best)
+		}
+		if entropy, _ := current.EntropyOf(turn.Result.Word); turn.Chosen.Word != turn.Result.Word || math.Abs(turn.Chosen.Entropy-entropy) > 1e-9 {
+			t.Errorf("turn %d: chose %s with entropy %f", i+1, turn.Chosen.Word, turn.Chosen.Entropy)
+		}
+
+		next, err := current.Prune(turn.Result)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if turn.Eliminated != len(current.Vocab)-len(next.Vocab) || len(turn.Sample) > 2 {
+			t.Errorf("turn %d: eliminated %d, sampled %v", i+1, turn.Eliminated, turn.Sample)
+		}
+		if few := len(next.Vocab) <= 1; few != (turn.Remaining != nil) {
+			t.Errorf("turn %d: %d left, listed %v", i+1, len(next.Vocab), turn.Remaining)
+		}
+		current = next
+	}
+
+	g := games.NewGame(primitives.MakeWord("light"))
+	g.Guess(primitives.MakeWord("zzzzz"))
+	if _, err := p.Explain(g, DefaultExplainOptions); err == nil {
+		t.Error("explained a guess outside the vocabulary")
+	}
+}
+
+func TestSampleEliminated(t *testing.T) {
+	before := dictionary("aaaaa", "bbbbb", "ccccc", "ddddd", "eeeee", "fffff", "ggggg")
+	after := dictionary("bbbbb", "eeeee")
+	eliminated := dictionary("aaaaa", "ccccc", "ddddd", "fffff", "ggggg")
+	tests := []struct {
+		n    int
+		want primitives.Dictionary
+	}{
+		{0, eliminated},
+		{5, eliminated},
+		{9, eliminated},
+		{2, dictionary("aaaaa", "ddddd")},
+		{3, dictionary("aaaaa", "ccccc", "fffff")},
+	}
+	for _, tt := range tests {
+		if got := sampleEliminated(before, after, tt.n); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("sample of %d is %v, want %v", tt.n, got, tt.want)
+		}
+	}
+	if got := sampleEliminated(before, before, 3); len(got) != 0 {
+		t.Errorf("nothing was eliminated but sampled %v", got)
+	}
+}
+
+func TestExplanation_Markdown(t *testing.T) {
+	_, e := explainedGame(t, ExplainOptions{Top: 1, Samples: 2, Few: 10})
+	md := e.Markdown(primitives.EmojiNotation)
+	for _, want := range []string{
+		"### Turn 1, answers left: 11",
+		"Top guesses by entropy:",
+		"| guess | entropy | worst bucket | P(answer) |",
+		"Guessed **flmns** " + e.Turns[1].Result.Pattern.Format(primitives.EmojiNotation),
+		"Remaining: light",
+		"**answer: light, won in 3**",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown has no %q:\n%s", want, md)
+		}
+	}
+
+	// the chosen guess is bold in the table, after the top candidate if it didn't make it
+	for i, turn := range e.Turns {
+		table := strings.Split(strings.Split(md, "### Turn")[i+1], "\n\nGuessed")[0]
+		rows := strings.Count(table, "\n| ") - 1
+		bold := "| **" + turn.Chosen.Word.String() + "** |"
+		if !strings.Contains(table, bold) {
+			t.Errorf("turn %d table doesn't bold %s:\n%s", i+1, turn.Chosen.Word, table)
+		}
+		want := 1
+		if turn.Candidates[0].Word != turn.Chosen.Word {
+			want = 2
+		}
+		if rows != want {
+			t.Errorf("turn %d table has %d rows, want %d:\n%s", i+1, rows, want, table)
+		}
+	}
+}
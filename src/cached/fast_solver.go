@@ -8,41 +8,73 @@ import (
 	"time"
 )
 
+// GuessOutcome is the trace of a single guess: the information the solver expected it to
+// give against what it did, in bits, and the number of answers left before and after
 type GuessOutcome struct {
-	res                      primitives.Result
-	expectedInfo, actualInfo float64
-	before, after            int
+	Result       primitives.Result `json:"result"`
+	ExpectedInfo float64           `json:"expected_info"`
+	ActualInfo   float64           `json:"actual_info"`
+	Before       int               `json:"before"`
+	After        int               `json:"after"`
+	// Uncertainty is the entropy in bits of the answers before the guess
+	Uncertainty float64 `json:"uncertainty"`
 }
 
 func MakeOutcome(score float64, result primitives.Result, prev, current *Patterns) GuessOutcome {
 	before, after := len(prev.Vocab), len(current.Vocab)
 	return GuessOutcome{
-		res:          result,
-		expectedInfo: score,
-		actualInfo:   -math.Log2(float64(after) / float64(before)),
-		before:       before,
-		after:        after,
+		Result:       result,
+		ExpectedInfo: score,
+		ActualInfo:   -math.Log2(float64(after) / float64(before)),
+		Before:       before,
+		After:        after,
+		Uncertainty:  prev.Uncertainty(),
 	}
 }
 
 func (gO GuessOutcome) String() string {
 	return fmt.Sprintf(
 		"%s:\n\tE(I): %.2f \t-> I: %.2f\n\tN(ans): %d \t-> N(ans): %d\n",
-		gO.res,
-		gO.expectedInfo, math.Abs(gO.actualInfo),
-		gO.before, gO.after,
+		gO.Result,
+		gO.ExpectedInfo, math.Abs(gO.ActualInfo),
+		gO.Before, gO.After,
 	)
 }
 
+// DefaultOpener is the first guess of every game, unless the vocabulary doesn't contain it
+var DefaultOpener = primitives.MakeWord("tares")
+
 type FastSolver struct {
 	Initial       *Patterns
+	Opener        primitives.Word
+	Strategy      Strategy
 	prev          *Patterns
 	current       *Patterns
 	guessMetadata []GuessOutcome
+	// openerInfo is the entropy of the Opener against the initial answers
+	openerInfo float64
 }
 
 func NewSolver(initial *Patterns) *FastSolver {
-	return &FastSolver{Initial: initial, current: initial}
+	f := &FastSolver{Initial: initial, current: initial, Opener: DefaultOpener, Strategy: Entropy{}}
+	if initial == nil {
+		return f
+	}
+	if err := f.SetOpener(DefaultOpener); err != nil {
+		// other variants (e.g. primel) need an opener from their own vocabulary
+		f.Opener, f.openerInfo = initial.GetBestGuess()
+	}
+	return f
+}
+
+// SetOpener changes the first guess of every game, it must be in the initial vocabulary
+func (f *FastSolver) SetOpener(opener primitives.Word) error {
+	info, ok := f.Initial.EntropyOf(opener)
+	if !ok {
+		return fmt.Errorf("opener %s is not in the vocabulary", opener)
+	}
+	f.Opener, f.openerInfo = opener, info
+	return nil
 }
 
 func (f *FastSolver) Reset() {
@@ -51,23 +83,50 @@ func (f *FastSolver) Reset() {
 	f.guessMetadata = []GuessOutcome{}
 }
 
-func (f *FastSolver) Solve(g *games.Game) (*games.Game, time.Duration) {
+// Solve plays the game to the end. It fails if a pattern leaves no answers, which happens
+// when the game is scored differently to the patterns.
+func (f *FastSolver) Solve(g *games.Game) (*games.Game, time.Duration, error) {
 	start := time.Now()
-	bestGuess, topScore := primitives.MakeWord("tares"), 0.0
-	f.guessOne(g, bestGuess, topScore)
-	for !(g.IsWon() || g.IsLost()) {
-		bestGuess, topScore := f.current.GetBestGuess()
-		f.guessOne(g, bestGuess, topScore)
+	err := f.guessOne(g, f.Opener, f.openerInfo)
+	for err == nil && !(g.IsWon() || g.IsLost()) {
+		bestGuess, topScore := ChooseWithin(f.Strategy, f.current, games.MaxGuesses-len(g.Results))
+		err = f.guessOne(g, bestGuess, topScore)
 	}
 	playDuration := time.Now().Sub(start)
-	return g, playDuration
+	return g, playDuration, err
 }
 
-func (f *FastSolver) guessOne(g *games.Game, bestGuess primitives.Word, topScore float64) {
+func (f *FastSolver) guessOne(g *games.Game, bestGuess primitives.Word, topScore float64) error {
 	result := primitives.Result{Pattern: g.Guess(bestGuess), Word: bestGuess}
-	f.current, f.prev = f.current.PruneAnswers(result), f.current
+	next, err := f.current.Prune(result)
+	if err != nil {
+		return err
+	}
+	f.current, f.prev = next, f.current
 	outcome := MakeOutcome(topScore, result, f.prev, f.current)
 	f.guessMetadata = append(f.guessMetadata, outcome)
+	return nil
+}
+
+// CurveSamples returns, for each guess of the last game solved, the uncertainty before the
+// guess and the number of guesses it took to finish from there. Games that were lost are
+// counted as taking one more guess than allowed.
+func (f FastSolver) CurveSamples(g *games.Game) []CurveSample {
+	total := len(f.guessMetadata)
+	if g.IsLost() {
+		total++
+	}
+	samples := make([]CurveSample, len(f.guessMetadata))
+	for turn, oc := range f.guessMetadata {
+		samples[turn] = CurveSample{Uncertainty: oc.Uncertainty, Guesses: total - turn}
+	}
+
+	return samples
+}
+
+// Trace returns the outcome of each guess of the last game solved
+func (f FastSolver) Trace() []GuessOutcome {
+	return append([]GuessOutcome{}, f.guessMetadata...)
 }
 
 func (f FastSolver) String() string {
@@ -0,0 +1,34 @@
+package cached
+
+import (
+	"bit-wordy/src/primitives"
+	"math/rand"
+)
+
+// dictionary makes a dictionary of the words
+func dictionary(words ...string) primitives.Dictionary {
+	dict := primitives.Dictionary{}
+	for _, w := range words {
+		dict = append(dict, primitives.MakeWord(w))
+	}
+	return dict
+}
+
+// randomDictionary makes n distinct words from the letters, always including the opener. A
+// small alphabet gives lots of shared letters and so long games.
+func randomDictionary(seed int64, letters string, n int) primitives.Dictionary {
+	rng := rand.New(rand.NewSource(seed))
+	seen := map[primitives.Word]bool{DefaultOpener: true}
+	dict := primitives.Dictionary{DefaultOpener}
+	for len(dict) < n {
+		w := primitives.Word{}
+		for i := range w {
+			w[i] = rune(letters[rng.Intn(len(letters))])
+		}
+		if !seen[w] {
+			seen[w] = true
+			dict = append(dict, w)
+		}
+	}
+	return dict
+}
@@ -8,6 +8,7 @@ import (
 	"log"
 	"math"
 	"os"
+	"sync"
 )
 
 const Cache = "data/cache"
@@ -18,25 +19,39 @@ type Patterns struct {
 	patternCache [][]byte
 	patternIndex *primitives.PatternSpace
 	fastLog      *FastLog
+	// weights is the prior probability of each word in Vocab being the answer, nil means
+	// every answer is equally likely
+	weights []float64
+	// root is the unpruned Patterns these were derived from, and rootIds the id in root of
+	// each word in Vocab. Both are nil for the root itself.
+	root    *Patterns
+	rootIds []int
+	// probes caches the row of every root word against the answers, see ProbeRows. It is
+	// nil for the root, whose rows are the patternCache.
+	probes *probeRows
+}
+
+// probeRows is built once, on first use, so Patterns stay safe for concurrent reads
+type probeRows struct {
+	once sync.Once
+	rows [][]byte
 }
 
 // BuildPatterns is the computation of all comparisons and the storage of the results
 func BuildPatterns(dict primitives.Dictionary) *Patterns {
-	patternCache := make([][]byte, len(dict))
-	// index := map[primitives.Word]int{}
-	for guessId, guess := range dict {
-		// index[answer] = guessId
-
-		guessOutcomes := make([]byte, len(dict))
-		for ansId, answer := range dict {
-			guessOutcomes[ansId] = answer.CheckGuess(guess).Byte()
-		}
-		patternCache[guessId] = guessOutcomes
-	}
+	return BuildPatternsWith(dict, primitives.Classic)
+}
+
+// BuildPatternsWith is BuildPatterns for any feedback model, e.g. primitives.Peaks
+func BuildPatternsWith(dict primitives.Dictionary, scoring primitives.Scoring) *Patterns {
+	patternCache := buildRows(dict, func(answer, guess primitives.Word) byte {
+		return scoring.Check(answer, guess).Byte()
+	})
 
 	p := &Patterns{
 		Vocab:        dict,
 		patternCache: patternCache,
+		fastLog:      NewFastLog(dict),
 	}
 	p.PopulateIndices(dict)
 	return p
@@ -53,7 +68,13 @@ func (p *Patterns) PopulateIndices(vocab primitives.Dictionary) {
 
 // LoadPatterns will build the patterns from the cached computed value if the cache exists
 func LoadPatterns(dict primitives.Dictionary) (*Patterns, error) {
-	file, err := os.Open(Cache)
+	return LoadPatternsFrom(Cache, dict)
+}
+
+// LoadPatternsFrom is LoadPatterns for a cache at an arbitrary path, e.g. one built for
+// a different dictionary
+func LoadPatternsFrom(path string, dict primitives.Dictionary) (*Patterns, error) {
+	file, err := os.Open(path)
 	if err != nil {
 		return nil, err
 	}
@@ -71,6 +92,9 @@ func LoadPatterns(dict primitives.Dictionary) (*Patterns, error) {
 	if err != nil {
 		return nil, err
 	}
+	if len(c) != len(dict) {
+		return nil, fmt.Errorf("cache %s has %d rows, the dictionary has %d words", path, len(c), len(dict))
+	}
 
 	fresh.patternCache = c
 	fresh.fastLog = NewFastLog(dict)
@@ -109,21 +133,87 @@ func (p *Patterns) Compare(guess, ans primitives.Word) primitives.Pattern {
 	return primitives.PatternFrom(p.patternCache[iGuess][iAns])
 }
 
+// Root returns the unpruned Patterns, whose Vocab is every allowed guess
+func (p *Patterns) Root() *Patterns {
+	if p.root == nil {
+		return p
+	}
+	return p.root
+}
+
+// RootId converts an id in Vocab to the id of the same word in Root().Vocab
+func (p *Patterns) RootId(wordId int) int {
+	if p.rootIds == nil {
+		return wordId
+	}
+	return p.rootIds[wordId]
+}
+
+// GuessRow returns the pattern byte of the guess against each remaining answer, indexed by
+// answer id. The guess can be any word of the root vocabulary, not just a remaining answer,
+// ok is false if it isn't in the root vocabulary.
+func (p *Patterns) GuessRow(guess primitives.Word) (row []byte, ok bool) {
+	if guessId, ok := (*p.index)[guess]; ok {
+		return p.patternCache[guessId], true
+	}
+	root := p.Root()
+	rootGuessId, ok := (*root.index)[guess]
+	if !ok {
+		return nil, false
+	}
+	row = make([]byte, len(p.Vocab))
+	for ansId := range row {
+		row[ansId] = root.patternCache[rootGuessId][p.RootId(ansId)]
+	}
+
+	return row, true
+}
+
+// ProbeRows is GuessRow for every word of the root vocabulary, indexed by root id. The rows
+// are computed on first use and kept, as strategies score every allowed word each turn.
+func (p *Patterns) ProbeRows() [][]byte {
+	if p.probes == nil {
+		return p.patternCache
+	}
+	p.probes.once.Do(func() {
+		root, n := p.Root(), len(p.Vocab)
+		block := make([]byte, len(root.Vocab)*n)
+		p.probes.rows = make([][]byte, len(root.Vocab))
+		for rootGuessId, rootRow := range root.patternCache {
+			row := block[rootGuessId*n : (rootGuessId+1)*n : (rootGuessId+1)*n]
+			for ansId := range row {
+				row[ansId] = rootRow[p.RootId(ansId)]
+			}
+			p.probes.rows[rootGuessId] = row
+		}
+	})
+	return p.probes.rows
+}
+
+// Contains returns true if the word is in the vocabulary
+func (p *Patterns) Contains(word primitives.Word) bool {
+	_, ok := (*p.index)[word]
+	return ok
+}
+
 type FastLog struct {
 	cache []float64
 }
 
+// NewFastLog precomputes the logs up front, rather than on first use, so that it is read
+// only and one set of Patterns can be shared between goroutines
 func NewFastLog(dict primitives.Dictionary) *FastLog {
-	return &FastLog{make([]float64, len(dict)+1)}
+	cache := make([]float64, len(dict)+1)
+	for x := 2; x < len(cache); x++ {
+		cache[x] = math.Log2(float64(x))
+	}
+	return &FastLog{cache}
 }
 
 func (f *FastLog) Log2(x int) float64 {
 	if x == 1 {
 		return 0.0
 	}
-	if f.cache[x] == 0.0 {
-		f.cache[x] = math.Log2(float64(x))
-	}
 	return f.cache[x]
 }
 
@@ -155,6 +245,9 @@ func (f *FastLog) Log2(x int) float64 {
 // TL;DR: For the purposes of solving wordle, we are looking to maximise the change of entropy on receiving
 // the pattern and pruning answers. That amounts to choosing the guess with the greatest
 func (p Patterns) Entropies() []float64 {
+	if p.weights != nil {
+		return p.weightedEntropies()
+	}
 	wordCount := len(p.Vocab)
 
 	// for each guess, create the pattern frequency distribution
@@ -190,12 +283,152 @@ func (p Patterns) Entropies() []float64 {
 	return entropies
 }
 
-// GetBestGuess returns the highest scoring guess, along with its score
+// SetWeights sets the prior probability of each word in Vocab being the answer, the weights
+// are relative and are carried over when answers are pruned. Passing nil makes every answer
+// equally likely again.
+func (p *Patterns) SetWeights(weights []float64) {
+	p.weights = weights
+}
+
+// Reweighted returns a copy of the patterns with the answers weighted by a prior over the
+// root vocabulary, indexed like Root().Vocab, nil makes every answer equally likely. The copy
+// shares everything else, so it is cheap to make each turn.
+func (p *Patterns) Reweighted(prior []float64) *Patterns {
+	q := *p
+	q.weights = nil
+	if prior != nil {
+		q.weights = make([]float64, len(p.Vocab))
+		for wordId := range p.Vocab {
+			q.weights[wordId] = prior[p.RootId(wordId)]
+		}
+	}
+	return &q
+}
+
+// Weight is the prior weight of the word at wordId
+func (p Patterns) Weight(wordId int) float64 {
+	if p.weights == nil {
+		return 1
+	}
+	return p.weights[wordId]
+}
+
+// bucketWeights is the total prior weight of the answers giving each pattern for a guess
+func (p Patterns) bucketWeights(guessId int, buckets []float64) (total float64) {
+	for i := range buckets {
+		buckets[i] = 0
+	}
+	for ansId, pattern := range p.patternCache[guessId] {
+		w := p.Weight(ansId)
+		buckets[pattern] += w
+		total += w
+	}
+
+	return total
+}
+
+// weightedEntropies is Entropies where P(pattern) is the share of the prior weight of the
+// answers giving the pattern, rather than the share of the answers.
+func (p Patterns) weightedEntropies() []float64 {
+	entropies := make([]float64, len(p.Vocab))
+	buckets := make([]float64, len(p.patternIndex))
+	for guessId := range p.patternCache {
+		total := p.bucketWeights(guessId, buckets)
+		entropy := 0.0
+		for _, weight := range buckets {
+			if weight == 0 {
+				continue
+			}
+			probability := weight / total
+			entropy += -(probability * math.Log2(probability))
+		}
+		entropies[guessId] = entropy
+	}
+
+	return entropies
+}
+
+// ExpectedRemaining is the expected number of answers left after each guess, indexed by
+// guess id. It is the sum over patterns of P(pattern) × the answers giving that pattern,
+// with P(pattern) weighted by the prior.
+func (p Patterns) ExpectedRemaining() []float64 {
+	expected := make([]float64, len(p.Vocab))
+	buckets := make([]float64, len(p.patternIndex))
+	counts := make([]int, len(p.patternIndex))
+	for guessId, answers := range p.patternCache {
+		total := p.bucketWeights(guessId, buckets)
+		for i := range counts {
+			counts[i] = 0
+		}
+		for _, pattern := range answers {
+			counts[pattern]++
+		}
+		for pattern, weight := range buckets {
+			expected[guessId] += weight / total * float64(counts[pattern])
+		}
+	}
+
+	return expected
+}
+
+// EntropyOf is the entropy of any word of the root vocabulary as a guess against the
+// remaining answers, i.e. a single element of Entropies without computing the rest
+func (p *Patterns) EntropyOf(guess primitives.Word) (float64, bool) {
+	row, ok := p.GuessRow(guess)
+	if !ok {
+		return 0, false
+	}
+	buckets := make([]float64, len(p.patternIndex))
+	total := 0.0
+	for ansId, pattern := range row {
+		buckets[pattern] += p.Weight(ansId)
+		total += p.Weight(ansId)
+	}
+	entropy := 0.0
+	for _, weight := range buckets {
+		if weight == 0 {
+			continue
+		}
+		probability := weight / total
+		entropy += -(probability * math.Log2(probability))
+	}
+
+	return entropy, true
+}
+
+// WorstCases is the size of the largest group of answers sharing a pattern for each guess,
+// indexed by guess id. A minimax strategy would minimise it.
+func (p Patterns) WorstCases() []int {
+	worst := make([]int, len(p.Vocab))
+	counts := make([]int, len(p.patternIndex))
+	for guessId, answers := range p.patternCache {
+		for i := range counts {
+			counts[i] = 0
+		}
+		for _, pattern := range answers {
+			counts[pattern]++
+			if counts[pattern] > worst[guessId] {
+				worst[guessId] = counts[pattern]
+			}
+		}
+	}
+
+	return worst
+}
+
+// tieTolerance is how close two scores must be to be considered equal
+const tieTolerance = 1e-9
+
+// GetBestGuess returns the highest scoring guess, along with its score. Ties go to the guess
+// that is most likely to be the answer.
 func (p Patterns) GetBestGuess() (bestGuess primitives.Word, topScore float64) {
 	bestGuessId := 0
 	for guessId, score := range p.Entropies() {
-		if score > topScore {
+		switch {
+		case score > topScore+tieTolerance:
 			bestGuessId, topScore = guessId, score
+		case score > topScore-tieTolerance && p.Weight(guessId) > p.Weight(bestGuessId):
+			bestGuessId, topScore = guessId, math.Max(score, topScore)
 		}
 	}
 
@@ -205,41 +438,67 @@ func (p Patterns) GetBestGuess() (bestGuess primitives.Word, topScore float64) {
 
 // PruneAnswers returns
 func (p *Patterns) PruneAnswers(result primitives.Result) *Patterns {
+	patterns, err := p.Prune(result)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return patterns
+}
+
+// PruneHistory prunes the answers by each result of a game in turn
+func (p *Patterns) PruneHistory(results primitives.ResultSet) (*Patterns, error) {
+	current := p
+	for _, result := range results {
+		var err error
+		if current, err = current.Prune(result); err != nil {
+			return nil, err
+		}
+	}
+	return current, nil
+}
+
+// Prune is PruneAnswers returning an error, rather than exiting, when the guess isn't in the
+// vocabulary or the result leaves no answers
+func (p *Patterns) Prune(result primitives.Result) (*Patterns, error) {
 	newVocab := primitives.Dictionary{}
 	patternByte := result.Pattern.Byte()
-	guessId := (*p.index)[result.Word]
-	patternBytes := p.patternCache[guessId]
-	idMap := []int{}
-	for ansId, pattern := range patternBytes {
-		if pattern == patternByte {
-			newVocab = append(newVocab, p.Vocab[ansId])
-			idMap = append(idMap, ansId)
-		}
+	patternBytes, ok := p.GuessRow(result.Word)
+	if !ok {
+		return nil, fmt.Errorf("guess %s is not in the vocabulary", result.Word)
+	}
+	idMap := matching(patternBytes, patternByte)
+	for _, ansId := range idMap {
+		newVocab = append(newVocab, p.Vocab[ansId])
 	}
 
 	if len(newVocab) == 0 {
-		log.Fatal(fmt.Errorf("result %s gave no remaining answers after prune, from wordcount of %d", result, len(p.Vocab)))
+		return nil, fmt.Errorf("result %s gave no remaining answers after prune, from wordcount of %d", result, len(p.Vocab))
 	}
 
-	// deriving the new pattern cache from the previous game state here is 3x faster
-	// than supplying the vocabulary and repeating the checks for the refined set
-	newCache := make([][]byte, len(newVocab))
-	for newGuessId, oldGuessId := range idMap {
-		ansRow := make([]byte, len(idMap))
-		for newAnsId, oldAnsId := range idMap {
-			ansRow[newAnsId] = p.patternCache[oldGuessId][oldAnsId]
-		}
-		newCache[newGuessId] = ansRow
-	}
+	newCache := subRows(p.patternCache, idMap)
 	newIndex := &map[primitives.Word]int{}
 	for i, answer := range newVocab {
 		(*newIndex)[answer] = i
 	}
 
+	var newWeights []float64
+	if p.weights != nil {
+		newWeights = make([]float64, len(idMap))
+		for newId, oldId := range idMap {
+			newWeights[newId] = p.weights[oldId]
+		}
+	}
+
+	root, newRootIds := p.Root(), make([]int, len(idMap))
+	for newId, oldId := range idMap {
+		newRootIds[newId] = p.RootId(oldId)
+	}
+
 	patterns := &Patterns{
 		Vocab: newVocab, index: newIndex, patternCache: newCache, patternIndex: p.patternIndex, fastLog: p.fastLog,
+		weights: newWeights, root: root, rootIds: newRootIds, probes: &probeRows{},
 	}
 
 	// patterns := BuildPatterns(newVocab)
-	return patterns
+	return patterns, nil
 }
@@ -0,0 +1,45 @@
+package cached
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPatterns_ExpectedRemaining(t *testing.T) {
+	dict := dictionary("tares", "light", "might", "night", "sight", "crane", "batch", "match")
+	p := BuildPatterns(dict)
+
+	// each answer, in proportion to its weight, leaves the answers giving the same pattern
+	bruteForce := func(guessId int, weights []float64) float64 {
+		expected, total := 0.0, 0.0
+		for ansId, ans := range dict {
+			same := 0
+			for _, other := range dict {
+				if ans.CheckGuess(dict[guessId]) == other.CheckGuess(dict[guessId]) {
+					same++
+				}
+			}
+			expected += weights[ansId] * float64(same)
+			total += weights[ansId]
+		}
+		return expected / total
+	}
+	for _, weights := range [][]float64{
+		{1, 1, 1, 1, 1, 1, 1, 1},
+		{1, 1, 1, 1, 1, 1, 3, 3},
+		{0, 1, 1, 1, 0, 0, 0, 0},
+	} {
+		p.SetWeights(weights)
+		for guessId, expected := range p.ExpectedRemaining() {
+			if want := bruteForce(guessId, weights); math.Abs(expected-want) > 1e-9 {
+				t.Errorf("weighted %v: %s leaves %f answers, want %f", weights, dict[guessId], expected, want)
+			}
+		}
+	}
+
+	// light, might and night give tares the same pattern, with all the weight on them it is
+	// expected to leave the 3 of them
+	if expected := p.ExpectedRemaining()[0]; math.Abs(expected-3) > 1e-9 {
+		t.Errorf("tares leaves %f answers weighted to light, might and night, want 3", expected)
+	}
+}
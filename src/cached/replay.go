@@ -0,0 +1,41 @@
+package cached
+
+import (
+	"bit-wordy/src/games"
+	"fmt"
+	"time"
+)
+
+// Replay is a solved game saved with the solver's trace, it is what iter and bench write
+// out as json lines
+type Replay struct {
+	Game     *games.Game    `json:"game"`
+	Won      bool           `json:"won"`
+	Strategy string         `json:"strategy,omitempty"`
+	Scoring  string         `json:"scoring,omitempty"`
+	Duration time.Duration  `json:"duration_ns,omitempty"`
+	Trace    []GuessOutcome `json:"trace,omitempty"`
+}
+
+// Replay records the last game solved
+func (f FastSolver) Replay(g *games.Game, d time.Duration) Replay {
+	return Replay{
+		Game:     g,
+		Won:      g.IsWon(),
+		Strategy: f.Strategy.Name(),
+		Duration: d,
+		Trace:    f.Trace(),
+	}
+}
+
+// String re-renders the game, followed by the trace if there is one
+func (r Replay) String() string {
+	s := fmt.Sprintf("GAME:\n%s\n", r.Game)
+	if len(r.Trace) > 0 {
+		s += "SOLVER:\n"
+		for _, oc := range r.Trace {
+			s += oc.String()
+		}
+	}
+	return s
+}
@@ -0,0 +1,37 @@
+package cached
+
+import (
+	"bit-wordy/src/games"
+	"bit-wordy/src/primitives"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFastSolver_Replay(t *testing.T) {
+	solver := NewSolver(BuildPatterns(dictionary("tares", "light", "might", "night", "crane", "batch")))
+	g, d, err := solver.Solve(games.NewGame(primitives.MakeWord("night")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := solver.Replay(g, d)
+	if r.Game != g || r.Won != g.IsWon() || r.Strategy != "entropy" || r.Duration != d {
+		t.Errorf("replay %+v", r)
+	}
+	if len(r.Trace) != len(g.Results) {
+		t.Fatalf("traced %d guesses of %d", len(r.Trace), len(g.Results))
+	}
+	for i, oc := range r.Trace {
+		if oc.Result != g.Results[i] {
+			t.Errorf("guess %d traced as %s, played %s", i+1, oc.Result, g.Results[i])
+		}
+	}
+
+	if s := r.String(); !strings.Contains(s, "GAME:") || !strings.Contains(s, "SOLVER:") {
+		t.Errorf("replay renders as %q", s)
+	}
+	r.Trace, r.Duration = nil, time.Second
+	if s := r.String(); strings.Contains(s, "SOLVER:") {
+		t.Errorf("replay without a trace renders as %q", s)
+	}
+}
@@ -0,0 +1,89 @@
+package cached
+
+import (
+	"bit-wordy/src/primitives"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// ReverseMatch is an answer consistent with some share grids, Counts holds the number of
+// allowed guesses giving each row's pattern (for each grid in turn, win rows excluded) and
+// Paths is the log10 of their product, i.e. of the number of guess sequences that could have
+// produced the grids.
+type ReverseMatch struct {
+	Answer primitives.Word
+	Counts [][]int
+	Paths  float64
+}
+
+func (r ReverseMatch) String() string {
+	return fmt.Sprintf("%s\tpaths: 10^%.2f\tguesses per row: %v", r.Answer, r.Paths, r.Counts)
+}
+
+// ReverseSolve deduces which answers could have produced the grids: an answer is consistent
+// if, for every row of every grid, some allowed guess gives that row's pattern against it.
+// A win can only be the last row of a grid. The matches are ranked by the number of paths,
+// then by the prior weight of the answer.
+func (p *Patterns) ReverseSolve(grids ...[]primitives.Pattern) ([]ReverseMatch, error) {
+	win := primitives.Win.Byte()
+	for g, grid := range grids {
+		for row, pattern := range grid {
+			if pattern.Byte() == win && row != len(grid)-1 {
+				return nil, fmt.Errorf("grid %d wins on row %d but carries on", g+1, row+1)
+			}
+		}
+	}
+
+	// the number of allowed guesses giving each pattern, per answer, i.e. a histogram of each
+	// column of the cache. Rows are walked in order as they are contiguous in memory.
+	root := p.Root()
+	counts := make([][primitives.PatternCardinality]int, len(p.Vocab))
+	for _, guess := range root.Vocab {
+		row, _ := p.GuessRow(guess)
+		for ansId, pattern := range row {
+			counts[ansId][pattern]++
+		}
+	}
+
+	matches := []ReverseMatch{}
+	for ansId, answer := range p.Vocab {
+		match, consistent := ReverseMatch{Answer: answer}, true
+		for _, grid := range grids {
+			gridCounts := []int{}
+			for _, pattern := range grid {
+				if pattern.Byte() == win {
+					// only guessing the answer itself wins
+					continue
+				}
+				n := counts[ansId][pattern.Byte()]
+				if n == 0 {
+					consistent = false
+					break
+				}
+				gridCounts = append(gridCounts, n)
+				match.Paths += math.Log10(float64(n))
+			}
+			if !consistent {
+				break
+			}
+			match.Counts = append(match.Counts, gridCounts)
+		}
+		if consistent {
+			matches = append(matches, match)
+		}
+	}
+
+	weights := map[primitives.Word]float64{}
+	for wordId, word := range p.Vocab {
+		weights[word] = p.Weight(wordId)
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if math.Abs(matches[i].Paths-matches[j].Paths) < tieTolerance {
+			return weights[matches[i].Answer] > weights[matches[j].Answer]
+		}
+		return matches[i].Paths > matches[j].Paths
+	})
+
+	return matches, nil
+}
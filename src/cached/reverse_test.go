@@ -0,0 +1,86 @@
+package cached
+
+import (
+	"bit-wordy/src/games"
+	"bit-wordy/src/primitives"
+	"reflect"
+	"testing"
+)
+
+// TestReverseSolve checks the matches against a brute force count of the guesses giving
+// each row, over grids of games actually played
+func TestReverseSolve(t *testing.T) {
+	dict := randomDictionary(1, "aeilnrst", 300)
+	p := BuildPatterns(dict)
+	solver := NewSolver(p)
+
+	answer := dict[42]
+	grids := [][]primitives.Pattern{}
+	for _, opener := range []primitives.Word{dict[0], dict[7], dict[99]} {
+		if err := solver.SetOpener(opener); err != nil {
+			t.Fatal(err)
+		}
+		solver.Reset()
+		g, _, err := solver.Solve(games.NewGame(answer))
+		if err != nil {
+			t.Fatal(err)
+		}
+		grid := []primitives.Pattern{}
+		for _, r := range g.Results {
+			grid = append(grid, r.Pattern)
+		}
+		grids = append(grids, grid)
+	}
+
+	matches, err := p.ReverseSolve(grids...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := map[primitives.Word]ReverseMatch{}
+	for i, m := range matches {
+		found[m.Answer] = m
+		if i > 0 && m.Paths > matches[i-1].Paths+tieTolerance {
+			t.Errorf("%s ranks below %s with more paths", m.Answer, matches[i-1].Answer)
+		}
+	}
+	if _, ok := found[answer]; !ok {
+		t.Fatalf("the answer %s isn't a match", answer)
+	}
+
+	for _, candidate := range dict {
+		consistent, counts := true, [][]int{}
+		for _, grid := range grids {
+			gridCounts := []int{}
+			for _, pattern := range grid {
+				if pattern == primitives.Win {
+					continue
+				}
+				n := 0
+				for _, guess := range dict {
+					if p.Compare(guess, candidate) == pattern {
+						n++
+					}
+				}
+				consistent = consistent && n > 0
+				gridCounts = append(gridCounts, n)
+			}
+			counts = append(counts, gridCounts)
+		}
+		m, ok := found[candidate]
+		if ok != consistent {
+			t.Errorf("%s is a match: %v, is consistent: %v", candidate, ok, consistent)
+			continue
+		}
+		if ok && !reflect.DeepEqual(m.Counts, counts) {
+			t.Errorf("%s has counts %v, want %v", candidate, m.Counts, counts)
+		}
+	}
+}
+
+func TestReverseSolve_WinMustEndTheGrid(t *testing.T) {
+	p := BuildPatterns(dictionary("tares", "light", "might"))
+	grid := []primitives.Pattern{primitives.Win, primitives.Win}
+	if _, err := p.ReverseSolve(grid); err == nil {
+		t.Error("a grid carrying on after a win was solved")
+	}
+}
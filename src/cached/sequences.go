@@ -0,0 +1,233 @@
+package cached
+
+import (
+	"bit-wordy/src/primitives"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Sequence is a fixed series of guesses played regardless of the patterns they receive, and
+// the statistics of the partition of the answers by their joint pattern. The joint pattern of
+// an answer is the pattern bytes of each guess combined, so the order of the guesses doesn't
+// change the partition.
+type Sequence struct {
+	Words []primitives.Word
+	// Entropy is the expected information of the whole sequence in bits
+	Entropy float64
+	// Worst is the size of the largest group of answers sharing a joint pattern
+	Worst int
+	// Groups is the number of distinct joint patterns
+	Groups int
+	// ExpectedRemaining is the expected number of answers left after the sequence
+	ExpectedRemaining float64
+}
+
+func (s Sequence) String() string {
+	words := make([]string, len(s.Words))
+	for i, w := range s.Words {
+		words[i] = w.String()
+	}
+	return fmt.Sprintf(
+		"%s\tE(I): %.4f\tworst: %d\tgroups: %d\tE(remaining): %.2f",
+		strings.Join(words, " "), s.Entropy, s.Worst, s.Groups, s.ExpectedRemaining,
+	)
+}
+
+// partition is the remaining answers labelled by the joint pattern of a sequence. The labels
+// are relabelled densely into [0, len(answers)) so extending the partition by another guess
+// needs len(answers) × 243 buckets at most.
+type partition struct {
+	labels []int32
+}
+
+// joint combines the partition with the patterns of one more guess, calling visit with the
+// joint label of each answer. The labels are not dense.
+func (pt partition) joint(row []byte, visit func(ansId int, label int)) {
+	for ansId, pattern := range row {
+		visit(ansId, int(pt.labels[ansId])*primitives.PatternCardinality+int(pattern))
+	}
+}
+
+// sequenceSearch holds the scratch space for evaluating sequences over a Patterns
+type sequenceSearch struct {
+	p       *Patterns
+	weights []float64
+	counts  []int
+	touched []int
+}
+
+func newSequenceSearch(p *Patterns) *sequenceSearch {
+	size := len(p.Vocab) * primitives.PatternCardinality
+	return &sequenceSearch{p: p, weights: make([]float64, size), counts: make([]int, size)}
+}
+
+// evaluate scores the partition extended by the guess row, leaving the scratch space clean
+func (s *sequenceSearch) evaluate(pt partition, row []byte) (entropy float64, worst, groups int, remaining float64) {
+	total := 0.0
+	pt.joint(row, func(ansId, label int) {
+		if s.counts[label] == 0 {
+			s.touched = append(s.touched, label)
+		}
+		s.counts[label]++
+		s.weights[label] += s.p.Weight(ansId)
+		total += s.p.Weight(ansId)
+	})
+
+	for _, label := range s.touched {
+		probability := s.weights[label] / total
+		if probability > 0 {
+			entropy += -(probability * math.Log2(probability))
+		}
+		if s.counts[label] > worst {
+			worst = s.counts[label]
+		}
+		remaining += probability * float64(s.counts[label])
+		s.counts[label], s.weights[label] = 0, 0
+	}
+	groups, s.touched = len(s.touched), s.touched[:0]
+
+	return entropy, worst, groups, remaining
+}
+
+// extend returns the dense partition of the answers after one more guess
+func (s *sequenceSearch) extend(pt partition, row []byte) partition {
+	dense := map[int]int32{}
+	next := partition{labels: make([]int32, len(row))}
+	pt.joint(row, func(ansId, label int) {
+		id, ok := dense[label]
+		if !ok {
+			id = int32(len(dense))
+			dense[label] = id
+		}
+		next.labels[ansId] = id
+	})
+	return next
+}
+
+// candidate is a sequence in the beam along with its partition
+type candidate struct {
+	seq Sequence
+	pt  partition
+}
+
+// SequenceOrders are the ways sequences can be ranked, by name
+var SequenceOrders = map[string]func(a, b Sequence) bool{
+	"entropy": func(a, b Sequence) bool {
+		return a.Entropy > b.Entropy
+	},
+	"minimax": func(a, b Sequence) bool {
+		if a.Worst == b.Worst {
+			return a.Entropy > b.Entropy
+		}
+		return a.Worst < b.Worst
+	},
+}
+
+// BestSequences searches for the best fixed sequences of length guesses. Every word is tried
+// as the first guess (unless a prefix is given) but, as there are far too many combinations
+// to try them all, later guesses come from the pool of the best single words by entropy, and
+// only the best beam sequences of each length are extended.
+func (p *Patterns) BestSequences(
+	length, pool, beam int,
+	prefix []primitives.Word,
+	less func(a, b Sequence) bool,
+) ([]Sequence, error) {
+	if len(prefix) > length {
+		return nil, fmt.Errorf("the prefix has %d words, more than the sequence length %d", len(prefix), length)
+	}
+
+	search := newSequenceSearch(p)
+	start := candidate{pt: partition{labels: make([]int32, len(p.Vocab))}}
+	for _, word := range prefix {
+		row, ok := p.GuessRow(word)
+		if !ok {
+			return nil, fmt.Errorf("%s is not in the vocabulary", word)
+		}
+		start = search.step(start, word, row)
+	}
+
+	// every word is a candidate for the first free position, after that only the pool
+	entropies := p.Root().Entropies()
+	order := make([]int, len(entropies))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return entropies[order[i]] > entropies[order[j]] })
+	words := make(primitives.Dictionary, len(order))
+	for i, guessId := range order {
+		words[i] = p.Root().Vocab[guessId]
+	}
+	if pool > len(words) {
+		pool = len(words)
+	}
+
+	beamed := []candidate{start}
+	for position := len(prefix); position < length; position++ {
+		options := words[:pool]
+		if position == len(prefix) {
+			options = words
+		}
+
+		next, seen := []candidate{}, map[string]bool{}
+		for _, c := range beamed {
+			for _, word := range options {
+				key := sequenceKey(append(append([]primitives.Word{}, c.seq.Words...), word))
+				if seen[key] || contains(c.seq.Words, word) {
+					continue
+				}
+				seen[key] = true
+
+				row, _ := p.GuessRow(word)
+				seq := Sequence{Words: append(append([]primitives.Word{}, c.seq.Words...), word)}
+				seq.Entropy, seq.Worst, seq.Groups, seq.ExpectedRemaining = search.evaluate(c.pt, row)
+				next = append(next, candidate{seq: seq, pt: c.pt})
+			}
+		}
+		sort.SliceStable(next, func(i, j int) bool { return less(next[i].seq, next[j].seq) })
+		if len(next) > beam {
+			next = next[:beam]
+		}
+
+		// only the survivors need their partitions built
+		for i := range next {
+			seqWords := next[i].seq.Words
+			row, _ := p.GuessRow(seqWords[len(seqWords)-1])
+			next[i].pt = search.extend(next[i].pt, row)
+		}
+		beamed = next
+	}
+
+	sequences := make([]Sequence, len(beamed))
+	for i, c := range beamed {
+		sequences[i] = c.seq
+	}
+	return sequences, nil
+}
+
+// step appends the word to the candidate, scoring it and building its partition
+func (s *sequenceSearch) step(c candidate, word primitives.Word, row []byte) candidate {
+	seq := Sequence{Words: append(append([]primitives.Word{}, c.seq.Words...), word)}
+	seq.Entropy, seq.Worst, seq.Groups, seq.ExpectedRemaining = s.evaluate(c.pt, row)
+	return candidate{seq: seq, pt: s.extend(c.pt, row)}
+}
+
+// sequenceKey identifies the set of words, as the order doesn't change the partition
+func sequenceKey(words []primitives.Word) string {
+	keys := make([]string, len(words))
+	for i, w := range words {
+		keys[i] = w.String()
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, " ")
+}
+
+func contains(words []primitives.Word, word primitives.Word) bool {
+	for _, w := range words {
+		if w == word {
+			return true
+		}
+	}
+	return false
+}
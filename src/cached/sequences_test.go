@@ -0,0 +1,85 @@
+package cached
+
+import (
+	"bit-wordy/src/primitives"
+	"math"
+	"testing"
+)
+
+// bruteForceSequence scores the words by grouping the answers by their patterns
+func bruteForceSequence(dict primitives.Dictionary, words ...primitives.Word) Sequence {
+	groups := map[[4]primitives.Pattern]int{}
+	for _, ans := range dict {
+		key := [4]primitives.Pattern{}
+		for i, w := range words {
+			key[i] = ans.CheckGuess(w)
+		}
+		groups[key]++
+	}
+	seq := Sequence{Words: words, Groups: len(groups)}
+	n := float64(len(dict))
+	for _, count := range groups {
+		probability := float64(count) / n
+		seq.Entropy += -(probability * math.Log2(probability))
+		seq.ExpectedRemaining += probability * float64(count)
+		if count > seq.Worst {
+			seq.Worst = count
+		}
+	}
+	return seq
+}
+
+func TestPatterns_BestSequences(t *testing.T) {
+	dict := dictionary(
+		"tares", "light", "might", "night", "sight", "fight", "crane", "batch",
+		"match", "patch", "pious", "zesty", "dough", "wacky",
+	)
+	p := BuildPatterns(dict)
+	for name, less := range SequenceOrders {
+		// with the whole vocabulary in the pool and the beam the search is exhaustive
+		sequences, err := p.BestSequences(2, len(dict), len(dict)*len(dict), nil, less)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := len(dict) * (len(dict) - 1) / 2; len(sequences) != want {
+			t.Errorf("%s: %d sequences, want every pair of words, %d", name, len(sequences), want)
+		}
+		for i, seq := range sequences {
+			if i > 0 && less(seq, sequences[i-1]) {
+				t.Errorf("%s: %v ranks below %v", name, seq, sequences[i-1])
+			}
+			want := bruteForceSequence(dict, seq.Words...)
+			if seq.Groups != want.Groups || seq.Worst != want.Worst ||
+				math.Abs(seq.Entropy-want.Entropy) > 1e-9 || math.Abs(seq.ExpectedRemaining-want.ExpectedRemaining) > 1e-9 {
+				t.Errorf("%s: %v, want %v", name, seq, want)
+			}
+		}
+
+		// a narrow beam still finds sequences in order, each covering the answers correctly
+		narrow, err := p.BestSequences(3, 4, 3, dictionary("tares"), less)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(narrow) != 3 {
+			t.Errorf("%s: a beam of 3 kept %d sequences", name, len(narrow))
+		}
+		for i, seq := range narrow {
+			if len(seq.Words) != 3 || seq.Words[0] != DefaultOpener {
+				t.Errorf("%s: %v doesn't start with the prefix", name, seq)
+			}
+			if i > 0 && less(seq, narrow[i-1]) {
+				t.Errorf("%s: %v ranks below %v", name, seq, narrow[i-1])
+			}
+			if want := bruteForceSequence(dict, seq.Words...); seq.Groups != want.Groups || seq.Worst != want.Worst {
+				t.Errorf("%s: %v, want %v", name, seq, want)
+			}
+		}
+	}
+
+	if _, err := p.BestSequences(1, 4, 4, dictionary("tares", "light"), SequenceOrders["entropy"]); err == nil {
+		t.Error("searched with a prefix longer than the sequence")
+	}
+	if _, err := p.BestSequences(2, 4, 4, dictionary("zzzzz"), SequenceOrders["entropy"]); err == nil {
+		t.Error("searched with a prefix outside the vocabulary")
+	}
+}
@@ -0,0 +1,287 @@
+package cached
+
+import (
+	"bit-wordy/src/primitives"
+	"math"
+	"sort"
+)
+
+// Strategy chooses the next guess from the current game state. It returns the guess and the
+// information it is expected to give in bits, whatever the strategy actually optimised.
+type Strategy interface {
+	Name() string
+	Choose(p *Patterns) (guess primitives.Word, expectedInfo float64)
+}
+
+// Entropy is the default strategy, it guesses the remaining answer with the greatest entropy
+type Entropy struct{}
+
+func (Entropy) Name() string {
+	return "entropy"
+}
+
+func (Entropy) Choose(p *Patterns) (primitives.Word, float64) {
+	return p.GetBestGuess()
+}
+
+// Minimax guesses the remaining answer that leaves the fewest answers in the worst case, i.e.
+// whose largest group of answers sharing a pattern is smallest. Ties go to the greatest
+// entropy.
+type Minimax struct{}
+
+func (Minimax) Name() string {
+	return "minimax"
+}
+
+func (Minimax) Choose(p *Patterns) (primitives.Word, float64) {
+	worst, entropies := p.WorstCases(), p.Entropies()
+	best := 0
+	for guessId := range p.Vocab {
+		if worst[guessId] < worst[best] ||
+			(worst[guessId] == worst[best] && entropies[guessId] > entropies[best]+tieTolerance) {
+			best = guessId
+		}
+	}
+	return p.Vocab[best], entropies[best]
+}
+
+// ExpectedScore minimises the expected number of guesses left in the game. Any allowed word
+// may be guessed: a remaining answer can win straight away, whereas a probe word can only
+// narrow the answers down, but it may narrow them down far enough to be worth it. For a guess
+// with probability P of being the answer, the expected score is
+//
+//	E(score) = P + Σ  P(pattern) · (1 + f(H(pattern)))
+//	             patterns ≠ Win
+//
+// where H(pattern) is the uncertainty left in bits when the pattern comes back and f is the
+// expected number of guesses needed to resolve that uncertainty, fitted from benchmark runs.
+type ExpectedScore struct {
+	Curve Curve
+}
+
+func (ExpectedScore) Name() string {
+	return "expected"
+}
+
+func (e ExpectedScore) Choose(p *Patterns) (primitives.Word, float64) {
+	if len(p.Vocab) <= 2 {
+		// any remaining answer does at least as well as a probe
+		return p.GetBestGuess()
+	}
+
+	var (
+		root      = p.Root()
+		win       = primitives.Win.Byte()
+		buckets   = newBuckets(len(p.patternIndex))
+		bestGuess = p.Vocab[0]
+		bestScore = math.Inf(1)
+		bestWin   = 0.0
+		bestInfo  = 0.0
+	)
+	for guessId, row := range p.ProbeRows() {
+		guess := root.Vocab[guessId]
+		total := buckets.fill(p, row)
+
+		pWin, score, info := buckets.weight[win]/total, buckets.weight[win]/total, 0.0
+		for pattern, weight := range buckets.weight {
+			if weight == 0 {
+				continue
+			}
+			probability := weight / total
+			info += -(probability * math.Log2(probability))
+			if byte(pattern) == win {
+				continue
+			}
+			score += probability * (1 + e.Curve.Eval(buckets.uncertainty(pattern)))
+		}
+
+		// prefer the guess that could win when the scores tie
+		if score < bestScore-tieTolerance || (score < bestScore+tieTolerance && pWin > bestWin) {
+			bestGuess, bestScore, bestWin, bestInfo = guess, score, pWin, info
+		}
+	}
+
+	return bestGuess, bestInfo
+}
+
+// DefaultLookahead is how many of the best guesses by entropy Lookahead looks past
+const DefaultLookahead = 10
+
+// Lookahead looks a guess further than Entropy. Of the Candidates remaining answers with the
+// most entropy it guesses the one expected to give the most information over two turns, if
+// its pattern is followed by the best second guess for the answers it leaves:
+//
+//	I = H(guess) + Σ P(pattern) · max H(second | pattern)
+//	               patterns
+//
+// The second guesses are the remaining answers too. Ties go to the greatest entropy.
+type Lookahead struct {
+	// Candidates is how many first guesses are looked past, DefaultLookahead if zero
+	Candidates int
+}
+
+func (Lookahead) Name() string {
+	return "lookahead"
+}
+
+func (l Lookahead) Choose(p *Patterns) (primitives.Word, float64) {
+	if len(p.Vocab) <= 2 {
+		return p.GetBestGuess()
+	}
+	n := l.Candidates
+	if n <= 0 {
+		n = DefaultLookahead
+	}
+	entropies := p.Entropies()
+	order := make([]int, len(p.Vocab))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return entropies[order[i]] > entropies[order[j]] })
+	if n > len(order) {
+		n = len(order)
+	}
+
+	var (
+		win       = primitives.Win.Byte()
+		scratch   = newSubsetScratch(len(p.patternIndex))
+		best      = order[0]
+		bestScore = math.Inf(-1)
+	)
+	for _, guessId := range order[:n] {
+		groups, total := map[byte][]int{}, 0.0
+		for ansId, pattern := range p.patternCache[guessId] {
+			groups[pattern] = append(groups[pattern], ansId)
+			total += p.Weight(ansId)
+		}
+		score := entropies[guessId]
+		for pattern, ids := range groups {
+			if pattern == win || len(ids) == 1 || total == 0 {
+				// the game is over, or will be with the next guess
+				continue
+			}
+			weight, second := 0.0, 0.0
+			for _, ansId := range ids {
+				weight += p.Weight(ansId)
+			}
+			for _, row := range p.patternCache {
+				second = math.Max(second, scratch.entropy(p, row, ids))
+			}
+			score += weight / total * second
+		}
+		if score > bestScore+tieTolerance {
+			best, bestScore = guessId, score
+		}
+	}
+
+	return p.Vocab[best], entropies[best]
+}
+
+// subsetScratch is the space for the weighted entropy of a guess over a subset of the
+// answers, only the buckets used are reset
+type subsetScratch struct {
+	weight  []float64
+	touched []byte
+}
+
+func newSubsetScratch(size int) *subsetScratch {
+	return &subsetScratch{weight: make([]float64, size)}
+}
+
+// entropy is the entropy in bits of the patterns of the row against the answers with the ids
+func (s *subsetScratch) entropy(p *Patterns, row []byte, ids []int) (entropy float64) {
+	total := 0.0
+	for _, ansId := range ids {
+		pattern := row[ansId]
+		if s.weight[pattern] == 0 {
+			s.touched = append(s.touched, pattern)
+		}
+		w := p.Weight(ansId)
+		s.weight[pattern] += w
+		total += w
+	}
+	for _, pattern := range s.touched {
+		if w := s.weight[pattern]; w > 0 {
+			probability := w / total
+			entropy += -(probability * math.Log2(probability))
+		}
+		s.weight[pattern] = 0
+	}
+	s.touched = s.touched[:0]
+
+	return entropy
+}
+
+// Weighted plays as the Strategy with the answers weighted by a Prior over the root
+// vocabulary, whatever weights the patterns were given, so the same strategy can be compared
+// with and without the prior on the same patterns
+type Weighted struct {
+	Strategy Strategy
+	Prior    []float64
+}
+
+func (w Weighted) Name() string {
+	return "weighted"
+}
+
+func (w Weighted) Choose(p *Patterns) (primitives.Word, float64) {
+	return w.Strategy.Choose(p.Reweighted(w.Prior))
+}
+
+func (w Weighted) ChooseWithin(p *Patterns, guessesLeft int) (primitives.Word, float64) {
+	return ChooseWithin(w.Strategy, p.Reweighted(w.Prior), guessesLeft)
+}
+
+// buckets accumulates the prior weight of the answers giving each pattern for a guess, and
+// Σ w·log2(w) so the uncertainty within each bucket can be computed.
+type buckets struct {
+	weight, wLogW []float64
+}
+
+func newBuckets(size int) *buckets {
+	return &buckets{weight: make([]float64, size), wLogW: make([]float64, size)}
+}
+
+// fill resets the buckets and accumulates the row of patterns against the remaining answers
+func (b *buckets) fill(p *Patterns, row []byte) (total float64) {
+	for i := range b.weight {
+		b.weight[i], b.wLogW[i] = 0, 0
+	}
+	for ansId, pattern := range row {
+		w := p.Weight(ansId)
+		b.weight[pattern] += w
+		if w > 0 {
+			b.wLogW[pattern] += w * math.Log2(w)
+		}
+		total += w
+	}
+
+	return total
+}
+
+// uncertainty is the entropy in bits of the answers in the bucket, log2(n) for a uniform prior
+func (b *buckets) uncertainty(pattern int) float64 {
+	w := b.weight[pattern]
+	if w == 0 {
+		return 0
+	}
+	return math.Log2(w) - b.wLogW[pattern]/w
+}
+
+// Uncertainty is the entropy in bits of the remaining answers, log2(len(Vocab)) when every
+// answer is equally likely
+func (p Patterns) Uncertainty() float64 {
+	total, wLogW := 0.0, 0.0
+	for wordId := range p.Vocab {
+		w := p.Weight(wordId)
+		total += w
+		if w > 0 {
+			wLogW += w * math.Log2(w)
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+
+	return math.Log2(total) - wLogW/total
+}
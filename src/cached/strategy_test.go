@@ -0,0 +1,114 @@
+package cached
+
+import (
+	"bit-wordy/src/primitives"
+	"math"
+	"testing"
+)
+
+// bruteEntropy is the entropy in bits of the patterns of the guess against the answers, each
+// as likely as its weight
+func bruteEntropy(guess primitives.Word, answers primitives.Dictionary, weights []float64) float64 {
+	groups, total := map[primitives.Pattern]float64{}, 0.0
+	for i, ans := range answers {
+		groups[ans.CheckGuess(guess)] += weights[i]
+		total += weights[i]
+	}
+	entropy := 0.0
+	for _, w := range groups {
+		if w > 0 {
+			entropy -= w / total * math.Log2(w/total)
+		}
+	}
+	return entropy
+}
+
+// bruteLookahead is the information from the guess and then the best second guess from the
+// answers left by its pattern
+func bruteLookahead(guess primitives.Word, answers primitives.Dictionary) float64 {
+	uniform := func(n int) []float64 {
+		weights := make([]float64, n)
+		for i := range weights {
+			weights[i] = 1
+		}
+		return weights
+	}
+	groups := map[primitives.Pattern]primitives.Dictionary{}
+	for _, ans := range answers {
+		pattern := ans.CheckGuess(guess)
+		groups[pattern] = append(groups[pattern], ans)
+	}
+	info := bruteEntropy(guess, answers, uniform(len(answers)))
+	for _, group := range groups {
+		second := 0.0
+		for _, next := range answers {
+			second = math.Max(second, bruteEntropy(next, group, uniform(len(group))))
+		}
+		info += float64(len(group)) / float64(len(answers)) * second
+	}
+	return info
+}
+
+func TestLookahead_Choose(t *testing.T) {
+	dict := randomDictionary(3, "aeilnrst", 80)
+	p := BuildPatterns(dict)
+
+	guess, info := (Lookahead{Candidates: len(dict)}).Choose(p)
+	if want, _ := p.EntropyOf(guess); math.Abs(info-want) > 1e-9 {
+		t.Errorf("%s was given %f bits, want its entropy %f", guess, info, want)
+	}
+	best := 0.0
+	for _, w := range dict {
+		best = math.Max(best, bruteLookahead(w, dict))
+	}
+	if got := bruteLookahead(guess, dict); got < best-1e-9 {
+		t.Errorf("looked ahead to %s giving %f bits over two guesses, the best gives %f", guess, got, best)
+	}
+
+	// looking past a single candidate is the entropy strategy
+	entropy, _ := (Entropy{}).Choose(p)
+	if guess, _ := (Lookahead{Candidates: 1}).Choose(p); guess != entropy {
+		t.Errorf("looking past one candidate chose %s, entropy chose %s", guess, entropy)
+	}
+}
+
+func TestWeighted_Choose(t *testing.T) {
+	dict := dictionary("tares", "light", "might", "night", "sight", "crane", "batch", "match", "flmns")
+	// might is so likely that guessing it beats flmns, the best guess when every answer is
+	prior := []float64{0.1, 0.1, 5, 0.1, 0.1, 0.1, 0.1, 0.1, 0.1}
+	p := BuildPatterns(dict)
+	weighted := Weighted{Strategy: Entropy{}, Prior: prior}
+
+	guess, info := weighted.Choose(p)
+	best := 0.0
+	for _, w := range dict {
+		best = math.Max(best, bruteEntropy(w, dict, prior))
+	}
+	if math.Abs(info-best) > 1e-9 || math.Abs(bruteEntropy(guess, dict, prior)-best) > 1e-9 {
+		t.Errorf("weighted chose %s with %f bits, the best by the prior gives %f", guess, info, best)
+	}
+	if p.Weight(0) != 1 || p.Weight(1) != 1 {
+		t.Errorf("choosing by the prior changed the weights of the patterns")
+	}
+	if uniform, _ := (Entropy{}).Choose(p); guess.String() != "might" || uniform.String() != "flmns" {
+		t.Errorf("weighted chose %s and uniform chose %s, want might and flmns", guess, uniform)
+	}
+
+	// the prior is over the root vocabulary, so it still applies once the answers are pruned
+	pruned, err := p.Prune(primitives.Result{Word: dict[5], Pattern: dict[1].CheckGuess(dict[5])})
+	if err != nil {
+		t.Fatal(err)
+	}
+	weights := make([]float64, len(pruned.Vocab))
+	for id := range pruned.Vocab {
+		weights[id] = prior[pruned.RootId(id)]
+	}
+	_, info = weighted.ChooseWithin(pruned, 5)
+	best = 0.0
+	for _, w := range pruned.Vocab {
+		best = math.Max(best, bruteEntropy(w, pruned.Vocab, weights))
+	}
+	if math.Abs(info-best) > 1e-9 {
+		t.Errorf("weighted gave %f bits over %v, the best by the prior gives %f", info, pruned.Vocab, best)
+	}
+}
@@ -0,0 +1,152 @@
+package cached
+
+import (
+	"fmt"
+	"math"
+)
+
+// Code is a pattern in its base 3 encoding: a byte holds the 3^5 patterns of a word, a
+// uint16 the 3^8 patterns of a nerdle equation
+type Code interface {
+	~uint8 | ~uint16
+}
+
+// Table is the pattern cache for a vocabulary of any kind: the code of every word guessed
+// against every word as the answer, rows are indexed by guess id and columns by answer id.
+// Patterns adds the prior, the root vocabulary and the byte cache file on top of the same
+// layout, other variants such as nerdle use the Table as it is.
+type Table[W comparable, C Code] struct {
+	Vocab []W
+	index map[W]int
+	rows  [][]C
+	// cardinality is the number of possible codes
+	cardinality int
+}
+
+// BuildTable computes all comparisons between the words of the vocabulary, check returns
+// the code of the guess when ans is the answer and must be less than cardinality
+func BuildTable[W comparable, C Code](vocab []W, cardinality int, check func(ans, guess W) C) *Table[W, C] {
+	return newTable(vocab, buildRows(vocab, check), cardinality)
+}
+
+func newTable[W comparable, C Code](vocab []W, rows [][]C, cardinality int) *Table[W, C] {
+	index := make(map[W]int, len(vocab))
+	for i, w := range vocab {
+		index[w] = i
+	}
+	return &Table[W, C]{Vocab: vocab, index: index, rows: rows, cardinality: cardinality}
+}
+
+// buildRows is the code of every word guessed against every word
+func buildRows[W any, C Code](vocab []W, check func(ans, guess W) C) [][]C {
+	rows := make([][]C, len(vocab))
+	for guessId, guess := range vocab {
+		row := make([]C, len(vocab))
+		for ansId, answer := range vocab {
+			row[ansId] = check(answer, guess)
+		}
+		rows[guessId] = row
+	}
+	return rows
+}
+
+// Row is the code of the guess against each answer, ok is false if it isn't in the vocabulary
+func (t *Table[W, C]) Row(guess W) (row []C, ok bool) {
+	guessId, ok := t.index[guess]
+	if !ok {
+		return nil, false
+	}
+	return t.rows[guessId], true
+}
+
+// Entropies is the expected information in bits of each word as a guess, indexed by guess
+// id, see Patterns.Entropies for the derivation
+func (t *Table[W, C]) Entropies() []float64 {
+	entropies := make([]float64, len(t.Vocab))
+	counts := make([]int, t.cardinality)
+	for guessId, row := range t.rows {
+		entropies[guessId] = rowEntropy(row, counts)
+	}
+	return entropies
+}
+
+// GetBestGuess returns the guess with the greatest entropy, along with it. Ties go to the
+// earliest guess in the vocabulary.
+func (t *Table[W, C]) GetBestGuess() (bestGuess W, topScore float64) {
+	bestGuessId := 0
+	for guessId, score := range t.Entropies() {
+		if score > topScore+tieTolerance {
+			bestGuessId, topScore = guessId, score
+		}
+	}
+	return t.Vocab[bestGuessId], topScore
+}
+
+// Prune returns the table restricted to the answers that give the code for the guess
+func (t *Table[W, C]) Prune(guess W, code C) (*Table[W, C], error) {
+	row, ok := t.Row(guess)
+	if !ok {
+		return nil, fmt.Errorf("guess %v is not in the vocabulary", guess)
+	}
+	ids := matching(row, code)
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("guess %v gave no remaining answers after prune, from wordcount of %d", guess, len(t.Vocab))
+	}
+
+	vocab := make([]W, len(ids))
+	for newId, oldId := range ids {
+		vocab[newId] = t.Vocab[oldId]
+	}
+	return newTable(vocab, subRows(t.rows, ids), t.cardinality), nil
+}
+
+// matching is the ids of the answers giving the code in the row
+func matching[C Code](row []C, code C) []int {
+	ids := []int{}
+	for ansId, c := range row {
+		if c == code {
+			ids = append(ids, ansId)
+		}
+	}
+	return ids
+}
+
+// subRows restricts the rows to the ids, both as guesses and as answers. Deriving the
+// pruned cache from the previous one is 3x faster than repeating the checks for the
+// refined set.
+func subRows[C Code](rows [][]C, ids []int) [][]C {
+	sub := make([][]C, len(ids))
+	for newGuessId, oldGuessId := range ids {
+		row := make([]C, len(ids))
+		for newAnsId, oldAnsId := range ids {
+			row[newAnsId] = rows[oldGuessId][oldAnsId]
+		}
+		sub[newGuessId] = row
+	}
+	return sub
+}
+
+// RowEntropy is the entropy in bits of the codes a guess gives against some answers, every
+// answer being equally likely
+func RowEntropy[C Code](row []C, cardinality int) float64 {
+	return rowEntropy(row, make([]int, cardinality))
+}
+
+// rowEntropy is RowEntropy using counts, as long as the cardinality, as scratch space
+func rowEntropy[C Code](row []C, counts []int) float64 {
+	for i := range counts {
+		counts[i] = 0
+	}
+	for _, code := range row {
+		counts[code]++
+	}
+	n, entropy := float64(len(row)), 0.0
+	for _, count := range counts {
+		if count == 0 {
+			continue
+		}
+		probability := float64(count) / n
+		entropy += -(probability * math.Log2(probability))
+	}
+	return entropy
+}
@@ -0,0 +1,112 @@
+package cached
+
+import (
+	"bit-wordy/src/games"
+	"bit-wordy/src/primitives"
+	"math"
+)
+
+// TurnStrategy is a Strategy that also takes into account how many guesses are left
+type TurnStrategy interface {
+	Strategy
+	ChooseWithin(p *Patterns, guessesLeft int) (guess primitives.Word, expectedInfo float64)
+}
+
+// ChooseWithin asks the strategy for a guess, telling it how many guesses are left if it is
+// a TurnStrategy
+func ChooseWithin(s Strategy, p *Patterns, guessesLeft int) (primitives.Word, float64) {
+	if ts, ok := s.(TurnStrategy); ok {
+		return ts.ChooseWithin(p, guessesLeft)
+	}
+	return s.Choose(p)
+}
+
+// TrapCluster returns the ids of the largest family of remaining answers that differ only at
+// one position, such as the _ight words
+func (p *Patterns) TrapCluster() []int {
+	families := map[string][]int{}
+	largest := ""
+	for ansId, answer := range p.Vocab {
+		for i := range answer {
+			t := template(answer, i)
+			families[t] = append(families[t], ansId)
+			if len(families[t]) > len(families[largest]) {
+				largest = t
+			}
+		}
+	}
+
+	return families[largest]
+}
+
+// TrapAware avoids losing in a trap: a family of answers differing at one position with more
+// members than there are guesses left, which guessing the candidates one at a time may not
+// get through. It then probes with the word splitting the family into the most groups, which
+// under classic scoring is the word with the most of the family's distinguishing letters,
+// even if it can't be the answer itself. Ties go to the word with the most information about
+// all the remaining answers. In HardMode only remaining answers are probed with. Otherwise it
+// plays as the Fallback, or Entropy if that is nil.
+type TrapAware struct {
+	HardMode bool
+	Fallback Strategy
+}
+
+func (TrapAware) Name() string {
+	return "trap"
+}
+
+func (t TrapAware) fallback() Strategy {
+	if t.Fallback == nil {
+		return Entropy{}
+	}
+	return t.Fallback
+}
+
+// Choose assumes no guesses have been made yet, use ChooseWithin
+func (t TrapAware) Choose(p *Patterns) (primitives.Word, float64) {
+	return t.ChooseWithin(p, games.MaxGuesses)
+}
+
+func (t TrapAware) ChooseWithin(p *Patterns, guessesLeft int) (primitives.Word, float64) {
+	cluster := p.TrapCluster()
+	if len(cluster) <= guessesLeft || len(cluster) < 3 {
+		return ChooseWithin(t.fallback(), p, guessesLeft)
+	}
+
+	probes := p.Root().Vocab
+	if t.HardMode {
+		probes = p.Vocab
+	}
+	var (
+		best      primitives.Word
+		bestSplit = 0
+		bestInfo  = math.Inf(-1)
+		seen      = make([]bool, len(p.patternIndex))
+	)
+	for _, probe := range probes {
+		row, _ := p.GuessRow(probe)
+		split := 0
+		for i := range seen {
+			seen[i] = false
+		}
+		for _, ansId := range cluster {
+			if !seen[row[ansId]] {
+				seen[row[ansId]] = true
+				split++
+			}
+		}
+		if split < bestSplit {
+			continue
+		}
+		info, _ := p.EntropyOf(probe)
+		if split > bestSplit || info > bestInfo+tieTolerance {
+			best, bestSplit, bestInfo = probe, split, info
+		}
+	}
+	if bestSplit <= 1 {
+		// nothing tells the family apart
+		return ChooseWithin(t.fallback(), p, guessesLeft)
+	}
+
+	return best, bestInfo
+}
@@ -0,0 +1,77 @@
+package cached
+
+import (
+	"bit-wordy/src/games"
+	"bit-wordy/src/primitives"
+	"sort"
+	"testing"
+)
+
+// trapDictionary has eight _ight answers and a word, flmns, that isn't in the family but
+// tells five of them apart
+func trapDictionary() primitives.Dictionary {
+	return dictionary("tares", "light", "might", "night", "sight", "fight", "tight", "right", "wight", "flmns", "crane")
+}
+
+func TestPatterns_TrapCluster(t *testing.T) {
+	p := BuildPatterns(trapDictionary())
+	cluster := []string{}
+	for _, id := range p.TrapCluster() {
+		cluster = append(cluster, p.Vocab[id].String())
+	}
+	sort.Strings(cluster)
+	want := []string{"fight", "light", "might", "night", "right", "sight", "tight", "wight"}
+	if len(cluster) != len(want) {
+		t.Fatalf("trap cluster is %v, want %v", cluster, want)
+	}
+	for i := range want {
+		if cluster[i] != want[i] {
+			t.Fatalf("trap cluster is %v, want %v", cluster, want)
+		}
+	}
+}
+
+func TestTrapAware_ChooseWithin(t *testing.T) {
+	p := BuildPatterns(trapDictionary())
+	// light, might, night, fight and wight all give the same pattern for tares
+	light := primitives.MakeWord("light")
+	pruned, err := p.Prune(primitives.Result{Word: DefaultOpener, Pattern: light.CheckGuess(DefaultOpener)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pruned.Vocab) != 5 {
+		t.Fatalf("tares left %v", pruned.Vocab)
+	}
+
+	if guess, _ := (TrapAware{}).ChooseWithin(pruned, 3); guess.String() != "flmns" {
+		t.Errorf("probed with %s in a trap of %d with 3 guesses left, want flmns", guess, len(pruned.Vocab))
+	}
+	if guess, _ := (TrapAware{HardMode: true}).ChooseWithin(pruned, 3); !pruned.Contains(guess) {
+		t.Errorf("probed with %s in hard mode, which can't be the answer", guess)
+	}
+
+	want, _ := Entropy{}.Choose(pruned)
+	if guess, _ := (TrapAware{}).ChooseWithin(pruned, 5); guess != want {
+		t.Errorf("chose %s with enough guesses left, want the fallback's %s", guess, want)
+	}
+	want, _ = Minimax{}.Choose(pruned)
+	if guess, _ := (TrapAware{Fallback: Minimax{}}).ChooseWithin(pruned, 5); guess != want {
+		t.Errorf("chose %s with enough guesses left, want the minimax fallback's %s", guess, want)
+	}
+}
+
+func TestTrapAware_Solve(t *testing.T) {
+	p := BuildPatterns(trapDictionary())
+	solver := NewSolver(p)
+	solver.Strategy = TrapAware{}
+	for _, answer := range p.Vocab {
+		solver.Reset()
+		g, _, err := solver.Solve(games.NewGame(answer))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if g.IsLost() {
+			t.Errorf("lost %s playing %v", answer, g.Results)
+		}
+	}
+}
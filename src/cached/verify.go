@@ -0,0 +1,123 @@
+package cached
+
+import (
+	"bit-wordy/src/primitives"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+)
+
+// Mismatch is an entry of the cache that disagrees with fresh scoring, at row GuessId and
+// column AnsId
+type Mismatch struct {
+	Guess, Answer  primitives.Word
+	GuessId, AnsId int
+	Cached, Fresh  primitives.Pattern
+}
+
+func (m Mismatch) String() string {
+	return fmt.Sprintf(
+		"guess %s (row %d) against answer %s (column %d): cached %s, fresh %s",
+		m.Guess, m.GuessId, m.Answer, m.AnsId,
+		m.Cached.Format(primitives.LetterNotation), m.Fresh.Format(primitives.LetterNotation),
+	)
+}
+
+// Verification is the outcome of checking a cache
+type Verification struct {
+	Checked    int
+	Mismatches []Mismatch
+}
+
+// cell is the coordinates of an entry of the cache
+type cell struct {
+	guessId, ansId int
+}
+
+// Verify rescores the cache of the root patterns with the scoring and reports every entry
+// that differs, in row then column order. If sample is positive only that many randomly
+// chosen entries are checked, otherwise the whole cache is. The rows are shared out between
+// workers goroutines.
+func (p *Patterns) Verify(scoring primitives.Scoring, sample int, rng *rand.Rand, workers int) (Verification, error) {
+	root := p.Root()
+	n := len(root.Vocab)
+	if len(root.patternCache) != n {
+		return Verification{}, fmt.Errorf("the cache has %d rows for %d words", len(root.patternCache), n)
+	}
+	for guessId, row := range root.patternCache {
+		if len(row) != n {
+			return Verification{}, fmt.Errorf("row %d of the cache has %d columns for %d words", guessId, len(row), n)
+		}
+	}
+	if workers < 1 {
+		workers = 1
+	}
+
+	// each worker checks either every row i ≡ worker (mod workers), or its share of the sample
+	var samples [][]cell
+	if sample > 0 {
+		samples = make([][]cell, workers)
+		for i := 0; i < sample; i++ {
+			c := cell{guessId: rng.Intn(n), ansId: rng.Intn(n)}
+			samples[i%workers] = append(samples[i%workers], c)
+		}
+	}
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		v       Verification
+		rescore = func(c cell) (Mismatch, bool) {
+			guess, answer := root.Vocab[c.guessId], root.Vocab[c.ansId]
+			cached, fresh := root.patternCache[c.guessId][c.ansId], scoring.Check(answer, guess)
+			if cached == fresh.Byte() {
+				return Mismatch{}, false
+			}
+			return Mismatch{
+				Guess: guess, Answer: answer, GuessId: c.guessId, AnsId: c.ansId,
+				Cached: scoring.Decode(cached), Fresh: fresh,
+			}, true
+		}
+	)
+	for worker := 0; worker < workers; worker++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			checked, mismatches := 0, []Mismatch{}
+			check := func(c cell) {
+				checked++
+				if m, bad := rescore(c); bad {
+					mismatches = append(mismatches, m)
+				}
+			}
+			if samples != nil {
+				for _, c := range samples[worker] {
+					check(c)
+				}
+			} else {
+				for guessId := worker; guessId < n; guessId += workers {
+					for ansId := 0; ansId < n; ansId++ {
+						check(cell{guessId, ansId})
+					}
+				}
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			v.Checked += checked
+			v.Mismatches = append(v.Mismatches, mismatches...)
+		}(worker)
+	}
+	wg.Wait()
+
+	sort.Slice(v.Mismatches, func(i, j int) bool {
+		a, b := v.Mismatches[i], v.Mismatches[j]
+		if a.GuessId != b.GuessId {
+			return a.GuessId < b.GuessId
+		}
+		return a.AnsId < b.AnsId
+	})
+
+	return v, nil
+}
@@ -0,0 +1,70 @@
+package cached
+
+import (
+	"bit-wordy/src/primitives"
+	"math/rand"
+	"testing"
+)
+
+func TestPatterns_Verify(t *testing.T) {
+	dict := randomDictionary(3, "aeilnrst", 50)
+	n := len(dict)
+	for _, workers := range []int{0, 1, 3} {
+		p := BuildPatterns(dict)
+		v, err := p.Verify(primitives.Classic, 0, nil, workers)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v.Checked != n*n || len(v.Mismatches) != 0 {
+			t.Errorf("%d workers: a fresh cache checked %d entries with %d mismatches", workers, v.Checked, len(v.Mismatches))
+		}
+
+		// corrupt two entries, of a pruned patterns' root
+		p.patternCache[7][2] = primitives.Win.Byte()
+		p.patternCache[3][9] = 0
+		pruned, err := p.Prune(primitives.Result{Word: dict[0], Pattern: primitives.Classic.Check(dict[1], dict[0])})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v, err = pruned.Verify(primitives.Classic, 0, nil, workers); err != nil {
+			t.Fatal(err)
+		}
+		want := []cell{{3, 9}, {7, 2}}
+		if len(v.Mismatches) != len(want) {
+			t.Fatalf("%d workers: found %v, want %v", workers, v.Mismatches, want)
+		}
+		for i, m := range v.Mismatches {
+			if m.GuessId != want[i].guessId || m.AnsId != want[i].ansId {
+				t.Errorf("%d workers: mismatch %d is %s, want row %d column %d", workers, i, m, want[i].guessId, want[i].ansId)
+			}
+			if m.Guess != dict[m.GuessId] || m.Answer != dict[m.AnsId] || m.Fresh != dict[m.AnsId].CheckGuess(m.Guess) {
+				t.Errorf("%d workers: mismatch %s", workers, m)
+			}
+		}
+	}
+}
+
+func TestPatterns_VerifySample(t *testing.T) {
+	dict := randomDictionary(3, "aeilnrst", 50)
+	p := BuildPatterns(dict)
+	v, err := p.Verify(primitives.Classic, 300, rand.New(rand.NewSource(1)), 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Checked != 300 || len(v.Mismatches) != 0 {
+		t.Errorf("sampled %d entries with %d mismatches", v.Checked, len(v.Mismatches))
+	}
+
+	// the classic cache doesn't agree with peaks scoring
+	if v, err = p.Verify(primitives.Peaks, 0, nil, 2); err != nil || len(v.Mismatches) == 0 {
+		t.Errorf("peaks scoring found %d mismatches, %v", len(v.Mismatches), err)
+	}
+}
+
+func TestPatterns_VerifyShape(t *testing.T) {
+	p := BuildPatterns(dictionary("tares", "light", "might"))
+	p.patternCache[1] = p.patternCache[1][:2]
+	if _, err := p.Verify(primitives.Classic, 0, nil, 1); err == nil {
+		t.Error("verified a cache with a short row")
+	}
+}
@@ -6,11 +6,12 @@ import (
 )
 
 // Game represents an instance of a wordle game including its
-// internal game state
+// internal game state. It encodes to json as the answer and results, the
+// scoring function isn't saved.
 type Game struct {
-	Answer     primitives.Word
-	Results    primitives.ResultSet
-	CheckGuess func(guess, ans primitives.Word) primitives.Pattern
+	Answer     primitives.Word                                     `json:"answer"`
+	Results    primitives.ResultSet                                `json:"results"`
+	CheckGuess func(guess, ans primitives.Word) primitives.Pattern `json:"-"`
 }
 
 // NewGame returns a fresh game with the answer passed
@@ -21,6 +22,15 @@ func NewGame(answer primitives.Word) *Game {
 	}
 }
 
+// NewGameWith returns a fresh game with the answer passed, scored by the feedback model
+func NewGameWith(answer primitives.Word, scoring primitives.Scoring) *Game {
+	g := NewGame(answer)
+	g.CheckGuess = func(guess, ans primitives.Word) primitives.Pattern {
+		return scoring.Check(ans, guess)
+	}
+	return g
+}
+
 // String
 func (g Game) String() string {
 	outcome := "In Progress"
@@ -36,6 +46,9 @@ func (g Game) String() string {
 // the answer
 func (g *Game) Guess(word primitives.Word) primitives.Pattern {
 	pattern := g.Answer.CheckGuess(word)
+	if g.CheckGuess != nil {
+		pattern = g.CheckGuess(word, g.Answer)
+	}
 
 	result := primitives.Result{Word: word, Pattern: pattern}
 	g.Results = append(g.Results, result)
@@ -53,9 +66,12 @@ func (g Game) IsWon() bool {
 	return isWon
 }
 
+// MaxGuesses is the number of attempts a game allows
+const MaxGuesses = 6
+
 // IsLost returns true if the number of guesses gets to 6 and the answer is not found
 func (g Game) IsLost() bool {
-	isLost := len(g.Results) >= 6 && !g.IsWon()
+	isLost := len(g.Results) >= MaxGuesses && !g.IsWon()
 
 	return isLost
 }
@@ -0,0 +1,143 @@
+package games
+
+import (
+	"bit-wordy/src/primitives"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ShareOptions controls the text of a share grid
+type ShareOptions struct {
+	// Title is the name of the game in the header, Wordle if empty
+	Title string
+	// Number is the puzzle number, it is left out of the header if zero
+	Number int
+	// Notation is the squares to use, one of the emoji notations
+	Notation primitives.Notation
+	// HardMode adds the asterisk after the score
+	HardMode bool
+}
+
+// Share is a share grid: the header and the pattern of each guess, with no letters
+type Share struct {
+	Title    string
+	Number   int
+	Guesses  int
+	Lost     bool
+	HardMode bool
+	Patterns []primitives.Pattern
+}
+
+// Share exports the game as the standard shareable text block, e.g.
+//
+//	Wordle 123 4/6
+//
+//	⬛🟨⬛⬛⬛
+//	⬛⬛🟩🟨⬛
+//	🟩🟩🟩⬛🟩
+//	🟩🟩🟩🟩🟩
+func (g Game) Share(opts ShareOptions) string {
+	s := Share{
+		Title:    opts.Title,
+		Number:   opts.Number,
+		Guesses:  len(g.Results),
+		Lost:     g.IsLost(),
+		HardMode: opts.HardMode,
+	}
+	for _, result := range g.Results {
+		s.Patterns = append(s.Patterns, result.Pattern)
+	}
+
+	return s.Format(opts.Notation)
+}
+
+// Format writes the share grid with the squares of the notation
+func (s Share) Format(n primitives.Notation) string {
+	title := s.Title
+	if title == "" {
+		title = "Wordle"
+	}
+	if s.Number != 0 {
+		title += " " + formatNumber(s.Number)
+	}
+	score := strconv.Itoa(s.Guesses)
+	if s.Lost {
+		score = "X"
+	}
+	header := fmt.Sprintf("%s %s/%d", title, score, MaxGuesses)
+	if s.HardMode {
+		header += "*"
+	}
+
+	rows := make([]string, len(s.Patterns))
+	for i, p := range s.Patterns {
+		rows[i] = p.Format(n)
+	}
+
+	return header + "\n\n" + strings.Join(rows, "\n") + "\n"
+}
+
+// formatNumber writes the puzzle number with thousands separators, as wordle does
+func formatNumber(n int) string {
+	digits := strconv.Itoa(n)
+	for i := len(digits) - 3; i > 0; i -= 3 {
+		digits = digits[:i] + "," + digits[i:]
+	}
+	return digits
+}
+
+// shareHeader matches e.g. "Wordle 1,234 4/6*", the puzzle number being optional
+var shareHeader = regexp.MustCompile(`^(.*?)\s*([\d,.]*)\s+([1-9X])/(\d+)(\*?)$`)
+
+// ParseShare reads a share grid back into its patterns. Lines that aren't the header or a
+// row of squares, such as a link pasted along with the grid, are ignored.
+func ParseShare(text string) (Share, error) {
+	s := Share{}
+	header := false
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if !header {
+			if m := shareHeader.FindStringSubmatch(line); m != nil {
+				header = true
+				s.Title, s.HardMode = m[1], m[5] == "*"
+				if m[2] != "" {
+					number, err := strconv.Atoi(strings.NewReplacer(",", "", ".", "").Replace(m[2]))
+					if err != nil {
+						return s, fmt.Errorf("puzzle number %q: %w", m[2], err)
+					}
+					s.Number = number
+				}
+				if m[3] == "X" {
+					s.Lost = true
+				} else {
+					s.Guesses, _ = strconv.Atoi(m[3])
+				}
+				continue
+			}
+		}
+		if p, err := primitives.ParsePattern(line); err == nil {
+			s.Patterns = append(s.Patterns, p)
+		}
+	}
+
+	if len(s.Patterns) == 0 {
+		return s, fmt.Errorf("no rows of squares found")
+	}
+	if !header {
+		// a bare grid, the score is implied by the rows
+		s.Guesses = len(s.Patterns)
+		s.Lost = s.Patterns[len(s.Patterns)-1] != primitives.Win
+	}
+	if s.Lost {
+		s.Guesses = len(s.Patterns)
+	} else if s.Guesses != len(s.Patterns) {
+		return s, fmt.Errorf("the header says %d guesses but there are %d rows", s.Guesses, len(s.Patterns))
+	}
+
+	return s, nil
+}
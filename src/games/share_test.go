@@ -0,0 +1,100 @@
+package games
+
+import (
+	"bit-wordy/src/primitives"
+	"reflect"
+	"testing"
+)
+
+func playGame(answer string, guesses ...string) *Game {
+	g := NewGame(primitives.MakeWord(answer))
+	for _, guess := range guesses {
+		g.Guess(primitives.MakeWord(guess))
+	}
+	return g
+}
+
+func TestGame_Share(t *testing.T) {
+	tests := []struct {
+		name string
+		game *Game
+		opts ShareOptions
+		want string
+	}{
+		{
+			name: "won",
+			game: playGame("light", "tares", "night", "light"),
+			opts: ShareOptions{Number: 1234, Notation: primitives.EmojiNotation},
+			want: "Wordle 1,234 3/6\n\n🟨⬛⬛⬛⬛\n⬛🟩🟩🟩🟩\n🟩🟩🟩🟩🟩\n",
+		},
+		{
+			name: "hard mode, light squares and a title",
+			game: playGame("light", "night", "light"),
+			opts: ShareOptions{Title: "Daily", Number: 7, Notation: primitives.LightEmojiNotation, HardMode: true},
+			want: "Daily 7 2/6*\n\n⬜🟩🟩🟩🟩\n🟩🟩🟩🟩🟩\n",
+		},
+		{
+			name: "lost, without a number",
+			game: playGame("light", "night", "might", "sight", "tight", "fight", "right"),
+			opts: ShareOptions{Notation: primitives.HighContrastEmojiNotation},
+			want: "Wordle X/6\n\n" +
+				"⬛🟧🟧🟧🟧\n⬛🟧🟧🟧🟧\n⬛🟧🟧🟧🟧\n🟦🟧🟧🟧🟧\n⬛🟧🟧🟧🟧\n⬛🟧🟧🟧🟧\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.game.Share(tt.opts)
+			if got != tt.want {
+				t.Fatalf("Share() =\n%s\nwant\n%s", got, tt.want)
+			}
+
+			// the grid reads back as the game's patterns
+			s, err := ParseShare(got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			patterns := []primitives.Pattern{}
+			for _, r := range tt.game.Results {
+				patterns = append(patterns, r.Pattern)
+			}
+			want := Share{
+				Title: tt.opts.Title, Number: tt.opts.Number, Guesses: len(patterns),
+				Lost: tt.game.IsLost(), HardMode: tt.opts.HardMode, Patterns: patterns,
+			}
+			if want.Title == "" {
+				want.Title = "Wordle"
+			}
+			if !reflect.DeepEqual(s, want) {
+				t.Errorf("ParseShare() = %+v, want %+v", s, want)
+			}
+		})
+	}
+}
+
+func TestParseShare(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		guesses int
+		lost    bool
+		wantErr bool
+	}{
+		{"pasted with a link", "Wordle 1.234 2/6\n\n⬛🟩🟩🟩🟩\n🟩🟩🟩🟩🟩\nhttps://example.com\n", 2, false, false},
+		{"bare grid", "⬛🟩🟩🟩🟩\n🟩🟩🟩🟩🟩", 2, false, false},
+		{"bare unfinished grid", "⬛🟩🟩🟩🟩\n⬛🟩🟩🟩🟩", 2, true, false},
+		{"variation selectors", "⬜️🟩🟩🟩🟩\n🟩🟩🟩🟩🟩", 2, false, false},
+		{"score disagrees with the rows", "Wordle 3/6\n\n⬛🟩🟩🟩🟩\n🟩🟩🟩🟩🟩", 0, false, true},
+		{"no rows", "Wordle 3/6\n", 0, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := ParseShare(tt.text)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseShare() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && (s.Guesses != tt.guesses || s.Lost != tt.lost) {
+				t.Errorf("ParseShare() = %d guesses, lost %v", s.Guesses, s.Lost)
+			}
+		})
+	}
+}
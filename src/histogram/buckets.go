@@ -0,0 +1,228 @@
+package histogram
+
+import (
+	patterns "bit-wordy/src/primitives"
+	"encoding/csv"
+	"fmt"
+	"github.com/fatih/color"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// New groups the results by their pattern, each result being a candidate answer and the
+// pattern a guess would give against it
+func New(results patterns.ResultSet) Histogram {
+	h := Histogram{}
+	for _, r := range results {
+		h[r.Pattern] = append(h[r.Pattern], r)
+	}
+
+	return h
+}
+
+// Bucket is one bar of the histogram with its share of the candidates. Bits is the
+// information gained when the guess gives this pattern.
+type Bucket struct {
+	Pattern     patterns.Pattern
+	Bar         Bar
+	Probability float64
+	Bits        float64
+}
+
+// Buckets returns the bars largest first, equal sizes in pattern order
+func (h Histogram) Buckets() []Bucket {
+	total := 0
+	for _, bar := range h {
+		total += len(bar)
+	}
+	buckets := make([]Bucket, 0, len(h))
+	for p, bar := range h {
+		probability := float64(len(bar)) / float64(total)
+		buckets = append(buckets, Bucket{
+			Pattern:     p,
+			Bar:         bar,
+			Probability: probability,
+			Bits:        -math.Log2(probability),
+		})
+	}
+	sort.Slice(buckets, func(i, j int) bool {
+		if len(buckets[i].Bar) != len(buckets[j].Bar) {
+			return len(buckets[i].Bar) > len(buckets[j].Bar)
+		}
+		return buckets[i].Pattern.Byte() < buckets[j].Pattern.Byte()
+	})
+
+	return buckets
+}
+
+// Entropy is the expected information of the guess, in bits
+func (h Histogram) Entropy() float64 {
+	entropy := 0.0
+	for _, b := range h.Buckets() {
+		entropy += b.Probability * b.Bits
+	}
+
+	return entropy
+}
+
+// Samples returns up to n of the bar's words
+func (b Bar) Samples(n int) []string {
+	samples := []string{}
+	for _, r := range b {
+		if len(samples) == n {
+			break
+		}
+		samples = append(samples, r.Word.String())
+	}
+
+	return samples
+}
+
+// Render draws the buckets as a bar chart fitting in width columns, listing up to samples
+// words from each
+func (h Histogram) Render(width, samples int) string {
+	rows := []Row{}
+	for _, b := range h.Buckets() {
+		rows = append(rows, Row{
+			Label: b.Pattern.Format(patterns.LetterNotation),
+			Count: len(b.Bar),
+			Note: fmt.Sprintf(
+				"%5d %5.1f%% %5.2f bits  %s",
+				len(b.Bar), 100*b.Probability, b.Bits, strings.Join(b.Bar.Samples(samples), " "),
+			),
+		})
+	}
+
+	return Chart(rows, width)
+}
+
+// Row is a bar of a Chart, the note is written after the bar
+type Row struct {
+	Label     string
+	Count     int
+	Note      string
+	Highlight bool
+}
+
+// Chart draws horizontal bars scaled to the largest count, fitting in width columns where
+// the labels and notes allow. Highlighted bars are drawn in green.
+func Chart(rows []Row, width int) string {
+	if len(rows) == 0 {
+		return ""
+	}
+
+	labelWidth, noteWidth, largest := 0, 0, 0
+	for _, r := range rows {
+		if n := utf8.RuneCountInString(r.Label); n > labelWidth {
+			labelWidth = n
+		}
+		if n := utf8.RuneCountInString(r.Note); n > noteWidth {
+			noteWidth = n
+		}
+		if r.Count > largest {
+			largest = r.Count
+		}
+	}
+
+	// two gaps of two columns either side of the bar
+	barWidth := width - labelWidth - noteWidth - 4
+	if barWidth < 10 {
+		barWidth = 10
+	}
+	highlight := color.New(color.FgGreen).SprintFunc()
+	lines := make([]string, len(rows))
+	for i, r := range rows {
+		length := 0
+		if largest > 0 {
+			length = int(math.Ceil(float64(barWidth) * float64(r.Count) / float64(largest)))
+		}
+		bar := strings.Repeat("█", length)
+		if r.Highlight {
+			bar = highlight(bar)
+		}
+		padding := strings.Repeat(" ", labelWidth-utf8.RuneCountInString(r.Label))
+		lines[i] = fmt.Sprintf("%s%s  %s%s  %s", r.Label, padding, bar, strings.Repeat(" ", barWidth-length), r.Note)
+	}
+
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// WriteCSV writes a row per bucket: the pattern, count, probability, bits and the sample
+// words separated by spaces
+func (h Histogram) WriteCSV(w io.Writer, samples int) error {
+	out := csv.NewWriter(w)
+	if err := out.Write([]string{"pattern", "count", "probability", "bits", "samples"}); err != nil {
+		return err
+	}
+	for _, b := range h.Buckets() {
+		err := out.Write([]string{
+			b.Pattern.Format(patterns.LetterNotation),
+			strconv.Itoa(len(b.Bar)),
+			strconv.FormatFloat(b.Probability, 'f', 6, 64),
+			strconv.FormatFloat(b.Bits, 'f', 4, 64),
+			strings.Join(b.Bar.Samples(samples), " "),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	out.Flush()
+
+	return out.Error()
+}
+
+// svgColors is the fill of each tile colour, the share grid colours
+var svgColors = map[patterns.Color]string{
+	patterns.Green:   "#6aaa64",
+	patterns.Yellow:  "#c9b458",
+	patterns.Grey:    "#787c7e",
+	patterns.Earlier: "#3a6fc4",
+	patterns.Later:   "#a450a4",
+}
+
+// WriteSVG draws the buckets as a horizontal bar chart, each bar labelled with its tiles
+func (h Histogram) WriteSVG(w io.Writer, title string, samples int) error {
+	const (
+		row, tile, barX, barWidth, textX = 22, 16, 110, 400, 520
+	)
+	buckets := h.Buckets()
+	height := row*(len(buckets)+1) + 10
+
+	svg := fmt.Sprintf(
+		`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" font-family="monospace" font-size="12">`+"\n",
+		textX+400, height,
+	)
+	svg += fmt.Sprintf(`<text x="4" y="16" font-weight="bold">%s</text>`+"\n", escapeXML(title))
+	largest := 1
+	if len(buckets) > 0 {
+		largest = len(buckets[0].Bar)
+	}
+	for i, b := range buckets {
+		y := row * (i + 1)
+		for j, c := range b.Pattern {
+			fill, ok := svgColors[c]
+			if !ok {
+				fill = svgColors[patterns.Grey]
+			}
+			svg += fmt.Sprintf(`<rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`, 4+j*(tile+2), y+2, tile, tile, fill)
+		}
+		length := math.Max(1, float64(barWidth)*float64(len(b.Bar))/float64(largest))
+		svg += fmt.Sprintf(`<rect x="%d" y="%d" width="%.1f" height="%d" fill="#4a4a4a"/>`, barX, y+2, length, tile)
+		svg += fmt.Sprintf(
+			`<text x="%d" y="%d">%d (%.1f%%, %.2f bits) %s</text>`+"\n",
+			textX, y+14, len(b.Bar), 100*b.Probability, b.Bits, escapeXML(strings.Join(b.Bar.Samples(samples), " ")),
+		)
+	}
+	svg += "</svg>\n"
+
+	_, err := io.WriteString(w, svg)
+	return err
+}
+
+func escapeXML(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;").Replace(s)
+}
@@ -0,0 +1,155 @@
+package histogram
+
+import (
+	patterns "bit-wordy/src/primitives"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// histogram is the patterns tares gives against the answers
+func histogram(answers ...string) Histogram {
+	dict := patterns.Dictionary{}
+	for _, w := range answers {
+		dict = append(dict, patterns.MakeWord(w))
+	}
+	return New(patterns.Matches(patterns.MakeWord("tares"), dict))
+}
+
+// light, might and night give the same pattern, as do batch and match
+var answers = []string{"light", "might", "night", "batch", "match", "crane", "pious", "tares"}
+
+func TestHistogram_Buckets(t *testing.T) {
+	h := histogram(answers...)
+	buckets := h.Buckets()
+	sizes := []int{}
+	total := 0
+	for i, b := range buckets {
+		sizes = append(sizes, len(b.Bar))
+		total += len(b.Bar)
+		if i > 0 && (len(b.Bar) > len(buckets[i-1].Bar) ||
+			len(b.Bar) == len(buckets[i-1].Bar) && b.Pattern.Byte() < buckets[i-1].Pattern.Byte()) {
+			t.Errorf("bucket %d is out of order", i)
+		}
+		for _, r := range b.Bar {
+			if r.Pattern != b.Pattern || r.Word.CheckGuess(patterns.MakeWord("tares")) != b.Pattern {
+				t.Errorf("%s is in the bucket of %s", r.Word, b.Pattern.Format(patterns.LetterNotation))
+			}
+		}
+		if want := float64(len(b.Bar)) / float64(len(answers)); math.Abs(b.Probability-want) > 1e-12 || math.Abs(b.Bits+math.Log2(want)) > 1e-12 {
+			t.Errorf("bucket %d has probability %f and %f bits", i, b.Probability, b.Bits)
+		}
+	}
+	if total != len(answers) || sizes[0] != 3 || sizes[1] != 2 || len(buckets) != 5 {
+		t.Errorf("bucket sizes %v", sizes)
+	}
+
+	// 3/8, 2/8 and three 1/8 buckets
+	want := -(3.0/8*math.Log2(3.0/8) + 2.0/8*math.Log2(2.0/8) + 3*1.0/8*math.Log2(1.0/8))
+	if math.Abs(h.Entropy()-want) > 1e-12 {
+		t.Errorf("entropy %f, want %f", h.Entropy(), want)
+	}
+	if samples := buckets[0].Bar.Samples(2); len(samples) != 2 {
+		t.Errorf("sampled %v", samples)
+	}
+}
+
+func TestChart(t *testing.T) {
+	if Chart(nil, 80) != "" {
+		t.Error("charted no rows")
+	}
+	rows := []Row{{Label: "1", Count: 4, Note: "4"}, {Label: "22", Count: 2, Note: "2"}, {Label: "3", Count: 0, Note: "0"}}
+	lines := strings.Split(strings.TrimSuffix(Chart(rows, 30), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("charted %d lines", len(lines))
+	}
+	// 30 columns less the labels, notes and gaps leave 23 for the bars
+	for i, want := range []int{23, 12, 0} {
+		if got := strings.Count(lines[i], "█"); got != want {
+			t.Errorf("bar %d is %d long, want %d:\n%s", i+1, got, want, lines[i])
+		}
+		if n := len([]rune(lines[i])); n != 30 {
+			t.Errorf("line %d is %d columns wide, want 30", i+1, n)
+		}
+	}
+	// the bars never shrink below 10 columns
+	if got := strings.Count(Chart(rows, 5), "█"); got != 10+5 {
+		t.Errorf("narrow chart has %d blocks", got)
+	}
+}
+
+func TestHistogram_WriteCSV(t *testing.T) {
+	h := histogram(answers...)
+	out := bytes.Buffer{}
+	if err := h.WriteCSV(&out, 2); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(&out).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1+len(h) || strings.Join(records[0], ",") != "pattern,count,probability,bits,samples" {
+		t.Fatalf("wrote %v", records)
+	}
+	for i, b := range h.Buckets() {
+		record := records[i+1]
+		p, err := patterns.ParsePattern(record[0])
+		if err != nil || p != b.Pattern {
+			t.Errorf("row %d has pattern %s", i+1, record[0])
+		}
+		if count, _ := strconv.Atoi(record[1]); count != len(b.Bar) {
+			t.Errorf("row %d has count %s, want %d", i+1, record[1], len(b.Bar))
+		}
+		if probability, _ := strconv.ParseFloat(record[2], 64); math.Abs(probability-b.Probability) > 1e-6 {
+			t.Errorf("row %d has probability %s", i+1, record[2])
+		}
+		if samples := strings.Fields(record[4]); len(samples) != int(math.Min(2, float64(len(b.Bar)))) {
+			t.Errorf("row %d has samples %q", i+1, record[4])
+		}
+	}
+}
+
+func TestHistogram_WriteSVG(t *testing.T) {
+	h := histogram(answers...)
+	out := bytes.Buffer{}
+	if err := h.WriteSVG(&out, `tares <"&"> bars`, 3); err != nil {
+		t.Fatal(err)
+	}
+
+	// the document is well formed, with a tile for each letter of each pattern and a bar
+	rects, texts := 0, []string{}
+	decoder := xml.NewDecoder(&out)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("invalid svg: %v", err)
+		}
+		switch el := token.(type) {
+		case xml.StartElement:
+			if el.Name.Local == "rect" {
+				rects++
+			}
+		case xml.CharData:
+			if s := strings.TrimSpace(string(el)); s != "" {
+				texts = append(texts, s)
+			}
+		}
+	}
+	if want := len(h) * (patterns.WordLength + 1); rects != want {
+		t.Errorf("drew %d rects, want %d", rects, want)
+	}
+	if len(texts) != 1+len(h) || texts[0] != `tares <"&"> bars` {
+		t.Errorf("wrote texts %q", texts)
+	}
+	if !strings.HasPrefix(texts[1], "3 (37.5%, 1.42 bits)") {
+		t.Errorf("the largest bar is labelled %q", texts[1])
+	}
+}
@@ -0,0 +1,21 @@
+package histogram
+
+import (
+	"os"
+	"strconv"
+)
+
+// DefaultWidth is the width of output that isn't going to a terminal
+const DefaultWidth = 100
+
+// TerminalWidth is the number of columns of the terminal on stdout, falling back to $COLUMNS
+// and then DefaultWidth when it can't be asked
+func TerminalWidth() int {
+	if width, ok := terminalWidth(); ok {
+		return width
+	}
+	if width, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && width > 0 {
+		return width
+	}
+	return DefaultWidth
+}
@@ -0,0 +1,8 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package histogram
+
+// terminalWidth can't ask the terminal, so leaves it to $COLUMNS
+func terminalWidth() (int, bool) {
+	return 0, false
+}
@@ -0,0 +1,234 @@
+// Package nerdle is the equation guessing variant of wordle, where every guess is an
+// eight character arithmetic expression that has to evaluate correctly.
+package nerdle
+
+import (
+	"bit-wordy/src/primitives"
+	"fmt"
+	"strconv"
+)
+
+// Length is the number of characters in an Equation
+const Length = 8
+
+// Symbols is the alphabet an Equation is made from
+var Symbols = primitives.NewAlphabet("nerdle", "0123456789+-*/=")
+
+// Equation is a guess or answer such as 12+35=47
+type Equation [Length]rune
+
+// MakeEquation uses the first eight runes of the input string to populate an Equation
+func MakeEquation(s string) Equation {
+	e := Equation{}
+	for i, r := range []rune(s) {
+		if i >= len(e) {
+			break
+		}
+		e[i] = r
+	}
+
+	return e
+}
+
+func (e Equation) String() string {
+	return string(e[:])
+}
+
+// CheckGuess returns the Pattern when a guess is compared to the answer e. As in nerdle, a
+// repeated symbol is only coloured as many times as it appears in the answer.
+func (e Equation) CheckGuess(guess Equation) Pattern {
+	p := Pattern{}
+	primitives.ScoreRepeats(e[:], guess[:], p[:])
+
+	return p
+}
+
+// Validate returns an error describing why the string isn't a correct equation, these
+// are the nerdle rules: one '=', a calculation on the left hand side containing at least
+// one operator, no leading or lone zeros in the calculation, no unary minus, and a
+// non-negative integer on the right hand side equal to the value of the calculation.
+func Validate(s string) error {
+	runes := []rune(s)
+	if len(runes) != Length {
+		return fmt.Errorf("%q is not %d characters long", s, Length)
+	}
+	for _, r := range runes {
+		if _, ok := Symbols.Symbol(r); !ok {
+			return fmt.Errorf("%q is not a valid character", r)
+		}
+	}
+
+	equals := -1
+	for i, r := range runes {
+		if r != '=' {
+			continue
+		}
+		if equals >= 0 {
+			return fmt.Errorf("%q has more than one '='", s)
+		}
+		equals = i
+	}
+	if equals < 0 {
+		return fmt.Errorf("%q has no '='", s)
+	}
+
+	lhs, rhs := string(runes[:equals]), string(runes[equals+1:])
+	result, err := number(rhs)
+	if err != nil {
+		return fmt.Errorf("right hand side: %w", err)
+	}
+	value, err := calculate(lhs)
+	if err != nil {
+		return fmt.Errorf("left hand side: %w", err)
+	}
+	if !value.isInt() || value.num != result {
+		return fmt.Errorf("%s does not equal %d", lhs, result)
+	}
+
+	return nil
+}
+
+// Valid is the boolean form of Validate
+func (e Equation) Valid() bool {
+	return Validate(e.String()) == nil
+}
+
+// number parses a run of digits without a leading zero
+func number(s string) (int64, error) {
+	if s == "" {
+		return 0, fmt.Errorf("missing number")
+	}
+	if len(s) > 1 && s[0] == '0' {
+		return 0, fmt.Errorf("%q has a leading zero", s)
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return 0, fmt.Errorf("%q is not a number", s)
+		}
+	}
+
+	return strconv.ParseInt(s, 10, 64)
+}
+
+// Evaluate computes the value of a calculation of numbers and the operators +-*/ with the
+// usual precedence. Division is exact, so intermediate values may be fractions, but the
+// value of the whole calculation must be a whole number.
+func Evaluate(expr string) (int64, error) {
+	value, err := calculate(expr)
+	if err != nil {
+		return 0, err
+	}
+	if !value.isInt() {
+		return 0, fmt.Errorf("%s is not a whole number", expr)
+	}
+
+	return value.num, nil
+}
+
+// calculate parses and evaluates the expression for Evaluate
+func calculate(expr string) (rational, error) {
+	var (
+		numbers []rational
+		ops     []rune
+		start   = 0
+	)
+	for i, r := range expr + "+" {
+		if r >= '0' && r <= '9' {
+			continue
+		}
+		n, err := number(expr[start:i])
+		if err != nil {
+			return rational{}, err
+		}
+		if n == 0 {
+			return rational{}, fmt.Errorf("%q has a lone zero", expr)
+		}
+		numbers = append(numbers, rational{n, 1})
+		if i < len(expr) {
+			ops = append(ops, r)
+		}
+		start = i + 1
+	}
+	if len(ops) == 0 {
+		return rational{}, fmt.Errorf("%q has no operator", expr)
+	}
+
+	return evaluate(numbers, ops)
+}
+
+// evaluate does multiplication and division left to right, then sums the terms
+func evaluate(numbers []rational, ops []rune) (rational, error) {
+	sum := rational{0, 1}
+	term := numbers[0]
+	sign := int64(1)
+	for i, op := range ops {
+		next := numbers[i+1]
+		switch op {
+		case '*':
+			term = term.mul(next)
+		case '/':
+			if next.num == 0 {
+				return rational{}, fmt.Errorf("division by zero")
+			}
+			term = term.div(next)
+		case '+', '-':
+			sum = sum.add(term.scale(sign))
+			term = next
+			sign = 1
+			if op == '-' {
+				sign = -1
+			}
+		default:
+			return rational{}, fmt.Errorf("%q is not an operator", op)
+		}
+	}
+
+	return sum.add(term.scale(sign)), nil
+}
+
+// rational is a fraction in lowest terms with a positive denominator
+type rational struct {
+	num, den int64
+}
+
+func makeRational(num, den int64) rational {
+	if den < 0 {
+		num, den = -num, -den
+	}
+	g := gcd(num, den)
+	return rational{num / g, den / g}
+}
+
+func gcd(a, b int64) int64 {
+	if a < 0 {
+		a = -a
+	}
+	for b != 0 {
+		a, b = b, a%b
+	}
+	if a == 0 {
+		return 1
+	}
+
+	return a
+}
+
+func (r rational) add(o rational) rational {
+	return makeRational(r.num*o.den+o.num*r.den, r.den*o.den)
+}
+
+func (r rational) mul(o rational) rational {
+	return makeRational(r.num*o.num, r.den*o.den)
+}
+
+func (r rational) div(o rational) rational {
+	return makeRational(r.num*o.den, r.den*o.num)
+}
+
+func (r rational) scale(k int64) rational {
+	return rational{r.num * k, r.den}
+}
+
+func (r rational) isInt() bool {
+	return r.den == 1
+}
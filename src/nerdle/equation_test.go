@@ -0,0 +1,74 @@
+package nerdle
+
+import (
+	"bit-wordy/src/primitives"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		equation string
+		valid    bool
+	}{
+		{"48-32=16", true},
+		{"10+20=30", true},
+		{"3*4/2=06", false},
+		{"12/3+4=8", true},
+		{"9/2*4=18", true},
+		{"1+2+3=6", false},
+		{"12+35=48", false},
+		{"12+35=47", true},
+		{"12+35+47", false},
+		{"1=1+0=1=", false},
+		{"0+12=012", false},
+		{"00+12=12", false},
+		{"-1+13=12", false},
+		{"12+0=012", false},
+		{"5*2-10=0", true},
+		{"123456=7", false},
+		{"12a35=47", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.equation, func(t *testing.T) {
+			if err := Validate(tt.equation); (err == nil) != tt.valid {
+				t.Errorf("Validate(%q) = %v, want valid %v", tt.equation, err, tt.valid)
+			}
+		})
+	}
+}
+
+// pattern reads a pattern written as g for green, y for yellow and . for grey
+func pattern(s string) Pattern {
+	p := Pattern{}
+	for i, r := range s {
+		switch r {
+		case 'g':
+			p[i] = primitives.Green
+		case 'y':
+			p[i] = primitives.Yellow
+		default:
+			p[i] = primitives.Grey
+		}
+	}
+	return p
+}
+
+func TestEquation_CheckGuessRepeats(t *testing.T) {
+	tests := []struct {
+		name, answer, guess, want string
+	}{
+		{"exact", "12+35=47", "12+35=47", "gggggggg"},
+		{"one yellow per answer symbol", "48-32=16", "11+11=22", "y....gy."},
+		{"greens use up the symbol first", "10+20=30", "11+11=22", "g.g..gy."},
+		{"repeated symbol in the answer", "11+11=22", "10+20=30", "g.gy.g.."},
+		{"no repeats", "48-32=16", "12+35=47", "yy.g.gy."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MakeEquation(tt.answer).CheckGuess(MakeEquation(tt.guess))
+			if got != pattern(tt.want) {
+				t.Errorf("%s against %s = %v, want %s", tt.guess, tt.answer, got.Code(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package nerdle
+
+import (
+	"strconv"
+)
+
+// operators are the arithmetic symbols allowed in the left hand side of an Equation
+var operators = []rune{'+', '-', '*', '/'}
+
+// Generate enumerates every valid Equation, i.e. all the calculations that fit in the left
+// hand side and whose value exactly fills the right hand side.
+func Generate() []Equation {
+	equations := []Equation{}
+	// the right hand side is at least one digit and '=' takes up a position
+	for lhsLength := 3; lhsLength <= Length-2; lhsLength++ {
+		rhsLength := Length - 1 - lhsLength
+		calculations(lhsLength, nil, nil, func(expr string, value rational) {
+			if !value.isInt() || value.num < 0 {
+				return
+			}
+			rhs := strconv.FormatInt(value.num, 10)
+			if len(rhs) != rhsLength {
+				return
+			}
+			equations = append(equations, MakeEquation(expr+"="+rhs))
+		})
+	}
+
+	return equations
+}
+
+// calculations calls emit with every calculation of exactly remaining characters that
+// continues the numbers and operators chosen so far.
+func calculations(
+	remaining int,
+	numbers []rational,
+	ops []rune,
+	emit func(expr string, value rational),
+) {
+	for digits := 1; digits <= remaining; digits++ {
+		left := remaining - digits
+		// an operator must be followed by at least one digit, and the calculation needs
+		// at least one operator
+		if left == 1 || (left == 0 && len(ops) == 0) {
+			continue
+		}
+
+		// zero is never an operand, and numbers have no leading zeros
+		lo, hi := int64(1), int64(9)
+		for i := 1; i < digits; i++ {
+			lo, hi = lo*10, hi*10+9
+		}
+
+		for n := lo; n <= hi; n++ {
+			nums := append(numbers[:len(numbers):len(numbers)], rational{n, 1})
+			if left == 0 {
+				value, err := evaluate(nums, ops)
+				if err == nil {
+					emit(render(nums, ops), value)
+				}
+				continue
+			}
+			for _, op := range operators {
+				calculations(left-1, nums, append(ops[:len(ops):len(ops)], op), emit)
+			}
+		}
+	}
+}
+
+// render writes the numbers interleaved with the operators
+func render(numbers []rational, ops []rune) string {
+	s := strconv.FormatInt(numbers[0].num, 10)
+	for i, op := range ops {
+		s += string(op) + strconv.FormatInt(numbers[i+1].num, 10)
+	}
+
+	return s
+}
@@ -0,0 +1,22 @@
+package nerdle
+
+import "testing"
+
+func TestGenerate(t *testing.T) {
+	equations := Generate()
+	seen := map[Equation]bool{}
+	for _, e := range equations {
+		if err := Validate(e.String()); err != nil {
+			t.Errorf("generated an invalid equation: %v", err)
+		}
+		if seen[e] {
+			t.Errorf("generated %s twice", e)
+		}
+		seen[e] = true
+	}
+	for _, e := range []string{"48-32=16", "10+20=30", "12/3+4=8", "5*2-10=0", "9/2*4=18"} {
+		if !seen[MakeEquation(e)] {
+			t.Errorf("%s was not generated", e)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package nerdle
+
+import (
+	"bit-wordy/src/cached"
+	"bit-wordy/src/primitives"
+)
+
+// PatternCardinality is the size of the 8 position pattern space, 3^8
+const PatternCardinality = 6561
+
+// Pattern is the colour of each character of a guessed Equation
+type Pattern [Length]primitives.Color
+
+// Win is the pattern of a correct guess
+var Win = Pattern{
+	primitives.Green, primitives.Green, primitives.Green, primitives.Green,
+	primitives.Green, primitives.Green, primitives.Green, primitives.Green,
+}
+
+// PatternFrom decodes a base 3 pattern code, Grey=0, Yellow=1, Green=2
+func PatternFrom(code uint16) Pattern {
+	p := Pattern{}
+	cols := []primitives.Color{primitives.Grey, primitives.Yellow, primitives.Green}
+	for j := range p {
+		p[j] = cols[code%3]
+		code /= 3
+	}
+
+	return p
+}
+
+// Code is the base 3 encoding of the pattern, it is less than PatternCardinality
+func (p Pattern) Code() uint16 {
+	code, base := uint16(0), uint16(1)
+	for _, color := range p {
+		switch color {
+		case primitives.Green:
+			code += 2 * base
+		case primitives.Yellow:
+			code += base
+		}
+		base *= 3
+	}
+
+	return code
+}
+
+func (p Pattern) String() string {
+	return Result{Equation: MakeEquation("########"), Pattern: p}.String()
+}
+
+// Result is a guess and the pattern it received
+type Result struct {
+	Equation Equation
+	Pattern  Pattern
+}
+
+func (r Result) String() string {
+	s := ""
+	for i, color := range r.Pattern {
+		s += color.Paint(r.Equation[i])
+	}
+	return s
+}
+
+// Patterns is the pattern cache of the candidate equations, the codes are uint16 as there
+// are more than 256 patterns
+type Patterns = cached.Table[Equation, uint16]
+
+// BuildPatterns computes all comparisons between the candidates. The cache is quadratic
+// in the number of candidates, so it's intended to be built once the opener has narrowed
+// down the full set of equations.
+func BuildPatterns(candidates []Equation) *Patterns {
+	return cached.BuildTable(candidates, PatternCardinality, check)
+}
+
+// check is the code of the guess when ans is the answer
+func check(ans, guess Equation) uint16 {
+	return ans.CheckGuess(guess).Code()
+}
+
+// Filter keeps the candidates that would have given the result, it is used before the
+// cache is small enough to build.
+func Filter(candidates []Equation, result Result) []Equation {
+	remaining := []Equation{}
+	for _, c := range candidates {
+		if c.CheckGuess(result.Equation) == result.Pattern {
+			remaining = append(remaining, c)
+		}
+	}
+
+	return remaining
+}
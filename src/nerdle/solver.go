@@ -0,0 +1,134 @@
+package nerdle
+
+import (
+	"bit-wordy/src/cached"
+	"fmt"
+	"time"
+)
+
+// MaxGuesses is the number of attempts a nerdle game allows
+const MaxGuesses = 6
+
+// Opener is the first guess of every game, it uses ten distinct symbols
+var Opener = MakeEquation("48-32=16")
+
+// Game is a nerdle game and its state
+type Game struct {
+	Answer  Equation
+	Results []Result
+}
+
+// NewGame returns a fresh game with the answer passed
+func NewGame(answer Equation) *Game {
+	return &Game{Answer: answer, Results: []Result{}}
+}
+
+// Guess scores the equation against the answer and records the result
+func (g *Game) Guess(guess Equation) Pattern {
+	pattern := g.Answer.CheckGuess(guess)
+	g.Results = append(g.Results, Result{Equation: guess, Pattern: pattern})
+
+	return pattern
+}
+
+// IsWon returns true if the latest guess was a winner
+func (g Game) IsWon() bool {
+	return len(g.Results) >= 1 && g.Results[len(g.Results)-1].Equation == g.Answer
+}
+
+// IsLost returns true if the guesses run out before the answer is found
+func (g Game) IsLost() bool {
+	return len(g.Results) >= MaxGuesses && !g.IsWon()
+}
+
+func (g Game) String() string {
+	outcome := "In Progress"
+	if g.IsWon() {
+		outcome = "Won!"
+	} else if g.IsLost() {
+		outcome = "Lost :("
+	}
+	return fmt.Sprintf("Answer: %s\n%s\nOutcome: %s\n", g.Answer, g.Results, outcome)
+}
+
+// CacheLimit is the largest candidate set the solver will build a pattern cache for,
+// above it the candidates are filtered by scoring them directly.
+const CacheLimit = 4000
+
+// SampleSize is the number of remaining candidates tried as the guess while there are too
+// many to cache, each is scored against every remaining candidate
+const SampleSize = 200
+
+// Solver plays nerdle by maximising entropy over the generated candidate equations
+type Solver struct {
+	Candidates []Equation
+	Opener     Equation
+}
+
+// NewSolver returns a solver over the candidates, typically the output of Generate
+func NewSolver(candidates []Equation) *Solver {
+	return &Solver{Candidates: candidates, Opener: Opener}
+}
+
+// Solve plays the game to completion
+func (s *Solver) Solve(g *Game) (*Game, time.Duration, error) {
+	start := time.Now()
+	remaining := s.Candidates
+	guess := s.Opener
+	var (
+		patterns *Patterns
+		err      error
+	)
+	for {
+		result := Result{Equation: guess, Pattern: g.Guess(guess)}
+		if g.IsWon() || g.IsLost() {
+			break
+		}
+
+		if patterns == nil {
+			remaining = Filter(remaining, result)
+			if len(remaining) == 0 {
+				return g, time.Since(start), fmt.Errorf("result %s eliminated every candidate", result)
+			}
+			if len(remaining) <= CacheLimit {
+				patterns = BuildPatterns(remaining)
+			}
+		} else if patterns, err = patterns.Prune(result.Equation, result.Pattern.Code()); err != nil {
+			return g, time.Since(start), err
+		}
+
+		if patterns == nil {
+			// still too many candidates to cache
+			guess = bestOfSample(remaining)
+			continue
+		}
+		guess, _ = patterns.GetBestGuess()
+	}
+
+	return g, time.Since(start), nil
+}
+
+// bestOfSample is the guess with the greatest entropy out of SampleSize of the candidates,
+// evenly spaced so that a game always plays the same way
+func bestOfSample(candidates []Equation) Equation {
+	step := len(candidates) / SampleSize
+	if step < 1 {
+		step = 1
+	}
+	var (
+		best     = candidates[0]
+		topScore = -1.0
+		row      = make([]uint16, len(candidates))
+	)
+	for i := 0; i < len(candidates); i += step {
+		guess := candidates[i]
+		for ansId, answer := range candidates {
+			row[ansId] = check(answer, guess)
+		}
+		if score := cached.RowEntropy(row, PatternCardinality); score > topScore {
+			best, topScore = guess, score
+		}
+	}
+
+	return best
+}
@@ -0,0 +1,41 @@
+package nerdle
+
+import (
+	"bit-wordy/src/cached"
+	"math/rand"
+	"testing"
+)
+
+func TestSolver_Solve(t *testing.T) {
+	candidates := Generate()
+	s := NewSolver(candidates)
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 40; i++ {
+		answer := candidates[rng.Intn(len(candidates))]
+		g, _, err := s.Solve(NewGame(answer))
+		if err != nil {
+			t.Fatalf("solving %s: %v", answer, err)
+		}
+		if !g.IsWon() {
+			t.Errorf("lost %s:\n%s", answer, g)
+		}
+	}
+}
+
+// TestBestOfSample checks the guess played while there are too many candidates to cache is
+// chosen by entropy, not just the first candidate
+func TestBestOfSample(t *testing.T) {
+	candidates := Generate()
+	entropy := func(guess Equation) float64 {
+		row := make([]uint16, len(candidates))
+		for ansId, answer := range candidates {
+			row[ansId] = check(answer, guess)
+		}
+		return cached.RowEntropy(row, PatternCardinality)
+	}
+
+	best := bestOfSample(candidates)
+	if entropy(best) <= entropy(candidates[0]) {
+		t.Errorf("sampled guess %s has no more entropy than %s", best, candidates[0])
+	}
+}
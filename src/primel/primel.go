@@ -0,0 +1,49 @@
+// Package primel generates the dictionary for Primel, the wordle variant
+// where every answer is a five digit prime number.
+package primel
+
+import (
+	"bit-wordy/src/primitives"
+	"strconv"
+)
+
+const (
+	// Lowest is the smallest number with five digits
+	Lowest = 10000
+	// Highest is the largest number with five digits
+	Highest = 99999
+)
+
+// Cache is where the primel pattern cache is dumped to and loaded from
+const Cache = "data/cache-primel"
+
+// Primes returns every prime p with lo <= p <= hi, using the sieve of Eratosthenes
+func Primes(lo, hi int) []int {
+	composite := make([]bool, hi+1)
+	primes := []int{}
+	for n := 2; n <= hi; n++ {
+		if composite[n] {
+			continue
+		}
+		if n >= lo {
+			primes = append(primes, n)
+		}
+		for m := n * n; m <= hi; m += n {
+			composite[m] = true
+		}
+	}
+
+	return primes
+}
+
+// Dictionary builds the primel vocabulary, every five digit prime as a Word over
+// the primitives.Digits alphabet
+func Dictionary() primitives.Dictionary {
+	primes := Primes(Lowest, Highest)
+	dict := make(primitives.Dictionary, len(primes))
+	for i, prime := range primes {
+		dict[i] = primitives.MakeWord(strconv.Itoa(prime))
+	}
+
+	return dict
+}
@@ -0,0 +1,36 @@
+package primel
+
+import (
+	"bit-wordy/src/primitives"
+	"reflect"
+	"testing"
+)
+
+func TestPrimes(t *testing.T) {
+	if got, want := Primes(1, 30), []int{2, 3, 5, 7, 11, 13, 17, 19, 23, 29}; !reflect.DeepEqual(got, want) {
+		t.Errorf("primes to 30 are %v, want %v", got, want)
+	}
+	if got, want := Primes(90, 110), []int{97, 101, 103, 107, 109}; !reflect.DeepEqual(got, want) {
+		t.Errorf("primes from 90 to 110 are %v, want %v", got, want)
+	}
+}
+
+func TestDictionary(t *testing.T) {
+	dict := Dictionary()
+	// there are 9592 primes below 100000 and 1229 below 10000
+	if len(dict) != 9592-1229 {
+		t.Fatalf("%d five digit primes, want %d", len(dict), 9592-1229)
+	}
+	if dict[0].String() != "10007" || dict[len(dict)-1].String() != "99991" {
+		t.Errorf("primes run from %s to %s", dict[0], dict[len(dict)-1])
+	}
+	for _, w := range dict {
+		symbols, err := primitives.Digits.Encode(w)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if primitives.Digits.Decode(symbols) != w {
+			t.Errorf("%s doesn't round trip the digits alphabet", w)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package primitives
+
+import "fmt"
+
+// Symbol is the compact id of a rune within an Alphabet
+type Symbol byte
+
+// Alphabet maps the runes a game variant allows onto compact symbol ids,
+// so that per-letter bookkeeping can be done with small arrays and bitsets
+// regardless of whether the letters are ASCII, accented or digits.
+type Alphabet struct {
+	Name    string
+	symbols []rune
+	ids     map[rune]Symbol
+	// dense is the symbol id plus one indexed by rune, zero if the rune isn't in the
+	// alphabet. It makes lookups cheap enough for building caches.
+	dense []int16
+}
+
+// NewAlphabet builds an Alphabet from the runes of the input string, in order.
+// Duplicate runes are ignored.
+func NewAlphabet(name, runes string) *Alphabet {
+	a := &Alphabet{Name: name, ids: map[rune]Symbol{}}
+	for _, r := range runes {
+		if _, exists := a.ids[r]; exists {
+			continue
+		}
+		a.ids[r] = Symbol(len(a.symbols))
+		a.symbols = append(a.symbols, r)
+	}
+
+	maxRune := rune(0)
+	for _, r := range a.symbols {
+		if r > maxRune {
+			maxRune = r
+		}
+	}
+	a.dense = make([]int16, maxRune+1)
+	for r, s := range a.ids {
+		a.dense[r] = int16(s) + 1
+	}
+
+	return a
+}
+
+var (
+	// Latin is the default english alphabet
+	Latin = NewAlphabet("latin", "abcdefghijklmnopqrstuvwxyz")
+	// Spanish adds ñ to the latin alphabet
+	Spanish = NewAlphabet("spanish", "abcdefghijklmnñopqrstuvwxyz")
+	// German adds the umlauts and ß to the latin alphabet
+	German = NewAlphabet("german", "abcdefghijklmnopqrstuvwxyzäöüß")
+	// Greek is the lower case greek alphabet, including final sigma
+	Greek = NewAlphabet("greek", "αβγδεζηθικλμνξοπρσςτυφχψω")
+	// Digits is the alphabet for numeric variants such as Primel
+	Digits = NewAlphabet("digits", "0123456789")
+)
+
+// Alphabets is the lookup of the built-in alphabets by name
+var Alphabets = map[string]*Alphabet{
+	Latin.Name:   Latin,
+	Spanish.Name: Spanish,
+	German.Name:  German,
+	Greek.Name:   Greek,
+	Digits.Name:  Digits,
+}
+
+// Len is the number of symbols in the alphabet
+func (a *Alphabet) Len() int {
+	return len(a.symbols)
+}
+
+// Symbol returns the id of the rune, ok is false if the rune is not in the alphabet
+func (a *Alphabet) Symbol(r rune) (s Symbol, ok bool) {
+	if r < 0 || int(r) >= len(a.dense) || a.dense[r] == 0 {
+		return 0, false
+	}
+	return Symbol(a.dense[r] - 1), true
+}
+
+// Rune is the inverse of Symbol
+func (a *Alphabet) Rune(s Symbol) rune {
+	return a.symbols[s]
+}
+
+// Runes returns the runes of the alphabet in symbol id order
+func (a *Alphabet) Runes() []rune {
+	return append([]rune{}, a.symbols...)
+}
+
+// Less orders runes by their position in the alphabet, runes outside the alphabet come
+// after it in code point order
+func (a *Alphabet) Less(x, y rune) bool {
+	sx, xOk := a.Symbol(x)
+	sy, yOk := a.Symbol(y)
+	switch {
+	case xOk && yOk:
+		return sx < sy
+	case xOk != yOk:
+		return xOk
+	default:
+		return x < y
+	}
+}
+
+// Valid returns true if every letter of the word is in the alphabet
+func (a *Alphabet) Valid(w Word) bool {
+	for _, r := range w {
+		if _, ok := a.Symbol(r); !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Encode converts the word to its symbol ids
+func (a *Alphabet) Encode(w Word) (symbols [WordLength]Symbol, err error) {
+	for i, r := range w {
+		s, ok := a.Symbol(r)
+		if !ok {
+			return symbols, fmt.Errorf("%q is not in the %s alphabet", r, a.Name)
+		}
+		symbols[i] = s
+	}
+
+	return symbols, nil
+}
+
+// Decode converts symbol ids back into a Word
+func (a *Alphabet) Decode(symbols [WordLength]Symbol) Word {
+	w := Word{}
+	for i, s := range symbols {
+		w[i] = a.Rune(s)
+	}
+
+	return w
+}
+
+func (a *Alphabet) String() string {
+	return fmt.Sprintf("%s: %s", a.Name, string(a.symbols))
+}
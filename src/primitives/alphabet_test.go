@@ -0,0 +1,110 @@
+package primitives
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestAlphabet_EncodeDecode(t *testing.T) {
+	tests := []struct {
+		alphabet *Alphabet
+		word     string
+		symbols  [WordLength]Symbol
+	}{
+		{Latin, "tares", [WordLength]Symbol{19, 0, 17, 4, 18}},
+		{Spanish, "ñandu", [WordLength]Symbol{14, 0, 13, 3, 21}},
+		{German, "größe", [WordLength]Symbol{6, 17, 27, 29, 4}},
+		{Greek, "κοσμς", [WordLength]Symbol{9, 14, 17, 11, 18}},
+		{Digits, "10007", [WordLength]Symbol{1, 0, 0, 0, 7}},
+	}
+	for _, tt := range tests {
+		w := MakeWord(tt.word)
+		symbols, err := tt.alphabet.Encode(w)
+		if err != nil || !tt.alphabet.Valid(w) {
+			t.Errorf("%s: %s isn't valid: %v", tt.alphabet.Name, tt.word, err)
+			continue
+		}
+		if symbols != tt.symbols {
+			t.Errorf("%s: %s encoded as %v, want %v", tt.alphabet.Name, tt.word, symbols, tt.symbols)
+		}
+		if decoded := tt.alphabet.Decode(symbols); decoded != w {
+			t.Errorf("%s: %s decoded as %s", tt.alphabet.Name, tt.word, decoded)
+		}
+	}
+}
+
+func TestAlphabet_InvalidSymbols(t *testing.T) {
+	tests := []struct {
+		alphabet *Alphabet
+		word     string
+	}{
+		{Latin, "tarés"},
+		{Latin, "TARES"},
+		{Latin, "tar3s"},
+		{Latin, "ñandu"},
+		{Greek, "λόγος"},
+		{Digits, "1000a"},
+	}
+	for _, tt := range tests {
+		w := MakeWord(tt.word)
+		if _, err := tt.alphabet.Encode(w); err == nil || tt.alphabet.Valid(w) {
+			t.Errorf("%s encoded %s", tt.alphabet.Name, tt.word)
+		}
+	}
+	for _, r := range []rune{-1, 'A', 'z' + 1, 'ñ', 0x10FFFF} {
+		if s, ok := Latin.Symbol(r); ok {
+			t.Errorf("latin has %q as %d", r, s)
+		}
+	}
+}
+
+func TestNewAlphabet(t *testing.T) {
+	a := NewAlphabet("test", "cabbac")
+	if a.Len() != 3 || !reflect.DeepEqual(a.Runes(), []rune("cab")) {
+		t.Errorf("alphabet of cabbac is %s", a)
+	}
+	for i, r := range "cab" {
+		if s, ok := a.Symbol(r); !ok || int(s) != i || a.Rune(s) != r {
+			t.Errorf("%q has symbol %d", r, s)
+		}
+	}
+	if !a.Less('c', 'a') || a.Less('a', 'c') || !a.Less('b', 'd') || !a.Less('d', 'e') {
+		t.Error("runes aren't in alphabet order, then code point order")
+	}
+}
+
+func TestLoadWordsFrom(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "words")
+	content := "tares\r\nniño\nñandu\nlight\ntoolong\nTARES\nmıght\n\nαβγδε\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		alphabet *Alphabet
+		want     []string
+	}{
+		{Latin, []string{"tares", "light"}},
+		{Spanish, []string{"tares", "ñandu", "light"}},
+		{Greek, []string{"αβγδε"}},
+		{Digits, nil},
+	}
+	for _, tt := range tests {
+		dict, err := LoadWordsFrom(path, tt.alphabet)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := []string{}
+		for _, w := range dict {
+			got = append(got, w.String())
+		}
+		if len(got) != len(tt.want) || (len(got) > 0 && !reflect.DeepEqual(got, tt.want)) {
+			t.Errorf("%s words are %v, want %v", tt.alphabet.Name, got, tt.want)
+		}
+	}
+
+	if _, err := LoadWordsFrom(filepath.Join(t.TempDir(), "missing"), Latin); err == nil {
+		t.Error("loaded a missing file")
+	}
+}
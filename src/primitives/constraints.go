@@ -0,0 +1,254 @@
+package primitives
+
+import (
+	"fmt"
+	"math/bits"
+	"sort"
+	"strings"
+)
+
+// MaxConstraintSymbols is the largest alphabet Constraints can handle, the allowed letters of
+// each position are a bitset over the symbol ids
+const MaxConstraintSymbols = 64
+
+// Constraints is what a game history says about the answer: the letters allowed at each
+// position and the minimum and maximum number of times each letter appears. A word matches
+// the constraints exactly when it would have given every pattern in the history, so they can
+// filter any word list without a pattern cache.
+//
+// With the classic scoring a tile is yellow whenever the letter appears anywhere in the answer,
+// so a guess only tells you whether each letter is present, and greens give a lower bound on
+// how many times. Peaks tiles narrow the letters allowed at their position.
+type Constraints struct {
+	alphabet *Alphabet
+	allowed  [WordLength]uint64
+	min, max []int
+}
+
+// NewConstraints derives the constraints from the results of a game
+func NewConstraints(results ResultSet, alphabet *Alphabet) (*Constraints, error) {
+	if alphabet.Len() > MaxConstraintSymbols {
+		return nil, fmt.Errorf("the %s alphabet has more than %d letters", alphabet.Name, MaxConstraintSymbols)
+	}
+	c := &Constraints{
+		alphabet: alphabet,
+		min:      make([]int, alphabet.Len()),
+		max:      make([]int, alphabet.Len()),
+	}
+	all := uint64(1)<<alphabet.Len() - 1
+	for i := range c.allowed {
+		c.allowed[i] = all
+	}
+	for s := range c.max {
+		c.max[s] = WordLength
+	}
+
+	for _, result := range results {
+		if err := c.add(result); err != nil {
+			return nil, err
+		}
+	}
+
+	// letters that can't appear anywhere are removed from every position
+	for s, max := range c.max {
+		if max == 0 {
+			for i := range c.allowed {
+				c.allowed[i] &^= 1 << s
+			}
+		}
+	}
+
+	return c, nil
+}
+
+// add narrows the constraints by a single guess
+func (c *Constraints) add(result Result) error {
+	symbols, err := c.alphabet.Encode(result.Word)
+	if err != nil {
+		return err
+	}
+
+	greens := make([]int, c.alphabet.Len())
+	present := make([]bool, c.alphabet.Len())
+	for i, color := range result.Pattern {
+		s := symbols[i]
+		switch color {
+		case Green:
+			c.allowed[i] = 1 << s
+			greens[s]++
+			present[s] = true
+		case Yellow:
+			c.allowed[i] &^= 1 << s
+			present[s] = true
+		case Earlier:
+			// only the letters before s, i.e. the bits below it
+			c.allowed[i] &= 1<<s - 1
+		case Later:
+			c.allowed[i] &^= 1<<(s+1) - 1
+		default:
+			c.max[s] = 0
+		}
+	}
+	for s := range greens {
+		if present[s] && c.min[s] < 1 {
+			c.min[s] = 1
+		}
+		if greens[s] > c.min[s] {
+			c.min[s] = greens[s]
+		}
+	}
+
+	return nil
+}
+
+// Matches returns true if the word could be the answer
+func (c *Constraints) Matches(w Word) bool {
+	var counts [MaxConstraintSymbols]int8
+	for i, r := range w {
+		s, ok := c.alphabet.Symbol(r)
+		if !ok || c.allowed[i]&(1<<s) == 0 {
+			return false
+		}
+		counts[s]++
+	}
+	for s := range c.min {
+		if int(counts[s]) < c.min[s] || int(counts[s]) > c.max[s] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Filter returns the words of the dictionary that match
+func (c *Constraints) Filter(dict Dictionary) Dictionary {
+	matching := Dictionary{}
+	for _, w := range dict {
+		if c.Matches(w) {
+			matching = append(matching, w)
+		}
+	}
+
+	return matching
+}
+
+// Explain lists the reasons the word can't be the answer, it is empty if the word matches
+func (c *Constraints) Explain(w Word) []string {
+	reasons := []string{}
+	counts := make([]int, c.alphabet.Len())
+	for i, r := range w {
+		s, ok := c.alphabet.Symbol(r)
+		if !ok {
+			reasons = append(reasons, fmt.Sprintf("%q is not in the %s alphabet", r, c.alphabet.Name))
+			continue
+		}
+		counts[s]++
+		if c.allowed[i]&(1<<s) == 0 {
+			reasons = append(reasons, fmt.Sprintf("position %d can't be %c", i+1, r))
+		}
+	}
+	for s := range c.min {
+		r := c.alphabet.Rune(Symbol(s))
+		switch {
+		case c.max[s] == 0 && counts[s] > 0:
+			reasons = append(reasons, fmt.Sprintf("the answer has no %c", r))
+		case counts[s] < c.min[s]:
+			reasons = append(reasons, fmt.Sprintf("the answer has at least %d %c, not %d", c.min[s], r, counts[s]))
+		case counts[s] > c.max[s]:
+			reasons = append(reasons, fmt.Sprintf("the answer has at most %d %c, not %d", c.max[s], r, counts[s]))
+		}
+	}
+
+	return reasons
+}
+
+// letters renders a bitset of symbols as its runes
+func (c *Constraints) letters(set uint64) string {
+	s := ""
+	for set != 0 {
+		symbol := bits.TrailingZeros64(set)
+		s += string(c.alphabet.Rune(Symbol(symbol)))
+		set &^= 1 << symbol
+	}
+
+	return s
+}
+
+// String is the human readable summary of the constraints
+func (c *Constraints) String() string {
+	lines := []string{}
+	all := uint64(1)<<c.alphabet.Len() - 1
+	for i, set := range c.allowed {
+		switch {
+		case set == 0:
+			lines = append(lines, fmt.Sprintf("position %d: nothing, the results contradict each other", i+1))
+		case bits.OnesCount64(set) == 1:
+			lines = append(lines, fmt.Sprintf("position %d: %s", i+1, c.letters(set)))
+		case bits.OnesCount64(set) <= c.alphabet.Len()/2:
+			lines = append(lines, fmt.Sprintf("position %d: one of %s", i+1, c.letters(set)))
+		case set != all:
+			lines = append(lines, fmt.Sprintf("position %d: not %s", i+1, c.letters(all&^set)))
+		default:
+			lines = append(lines, fmt.Sprintf("position %d: anything", i+1))
+		}
+	}
+
+	contains, excludes := []string{}, ""
+	for s := range c.min {
+		r := c.alphabet.Rune(Symbol(s))
+		switch {
+		case c.max[s] == 0:
+			excludes += string(r)
+		case c.min[s] == 1:
+			contains = append(contains, string(r))
+		case c.min[s] > 1:
+			contains = append(contains, fmt.Sprintf("%c (at least %d)", r, c.min[s]))
+		}
+	}
+	sort.Strings(contains)
+	if len(contains) > 0 {
+		lines = append(lines, "contains: "+strings.Join(contains, ", "))
+	}
+	if excludes != "" {
+		lines = append(lines, "excludes: "+excludes)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Regexp exports the constraints as a regular expression matching a whole word. The letter
+// counts are lookaheads, so it needs a PCRE engine (e.g. grep -P) rather than go's regexp. A
+// position with no letter allowed, from a history that contradicts itself, is written as the
+// empty lookahead (?!) which never matches.
+func (c *Constraints) Regexp() string {
+	re := "^"
+	for s := range c.min {
+		r := regexpEscape(c.alphabet.Rune(Symbol(s)))
+		if c.min[s] > 0 {
+			re += fmt.Sprintf("(?=(?:.*%s){%d})", r, c.min[s])
+		}
+		if c.max[s] > 0 && c.max[s] < WordLength {
+			re += fmt.Sprintf("(?!(?:.*%s){%d})", r, c.max[s]+1)
+		}
+	}
+	for _, set := range c.allowed {
+		if set == 0 {
+			re += "(?!)"
+			continue
+		}
+		class := ""
+		for _, r := range c.letters(set) {
+			class += regexpEscape(r)
+		}
+		re += "[" + class + "]"
+	}
+
+	return re + "$"
+}
+
+func regexpEscape(r rune) string {
+	if strings.ContainsRune(`\^$.|?*+()[]{}-/`, r) {
+		return `\` + string(r)
+	}
+	return string(r)
+}
@@ -0,0 +1,172 @@
+package primitives
+
+import (
+	"math/rand"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// history scores each guess against the answer
+func history(scoring Scoring, answer string, guesses ...string) ResultSet {
+	results := ResultSet{}
+	for _, g := range guesses {
+		guess := MakeWord(g)
+		results = append(results, Result{Word: guess, Pattern: scoring.Check(MakeWord(answer), guess)})
+	}
+	return results
+}
+
+func TestConstraints_Matches(t *testing.T) {
+	tests := []struct {
+		name     string
+		scoring  Scoring
+		answer   string
+		guesses  []string
+		match    []string
+		mismatch []string
+	}{
+		{
+			name: "greys and yellows", scoring: Classic, answer: "light", guesses: []string{"tares"},
+			match:    []string{"light", "night", "month"},
+			mismatch: []string{"tight", "pasty", "ghost"},
+		},
+		{
+			name: "two greens of a letter are a minimum count", scoring: Classic, answer: "sheep", guesses: []string{"steep"},
+			match:    []string{"sheep", "sleep", "sweep"},
+			mismatch: []string{"shelp", "steep", "sheee"},
+		},
+		{
+			name: "repeated guess letter", scoring: Classic, answer: "eerie", guesses: []string{"geese"},
+			match:    []string{"eerie", "aerie"},
+			mismatch: []string{"geese", "eerig", "aeeie"},
+		},
+		{
+			name: "peaks narrows each position", scoring: Peaks, answer: "light", guesses: []string{"moist"},
+			match:    []string{"light", "fight", "aight"},
+			mismatch: []string{"night", "moist", "lxght"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewConstraints(history(tt.scoring, tt.answer, tt.guesses...), Latin)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, w := range tt.match {
+				if !c.Matches(MakeWord(w)) {
+					t.Errorf("%s doesn't match: %v", w, c.Explain(MakeWord(w)))
+				}
+				if reasons := c.Explain(MakeWord(w)); len(reasons) > 0 {
+					t.Errorf("%s matches but is explained away: %v", w, reasons)
+				}
+			}
+			for _, w := range tt.mismatch {
+				if c.Matches(MakeWord(w)) {
+					t.Errorf("%s matches", w)
+				}
+				if len(c.Explain(MakeWord(w))) == 0 {
+					t.Errorf("%s doesn't match but has no explanation", w)
+				}
+			}
+		})
+	}
+}
+
+// TestConstraints_Exact checks a word matches the constraints exactly when it would have
+// given every pattern of the history, on a dictionary with lots of repeated letters
+func TestConstraints_Exact(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	dict := Dictionary{}
+	for len(dict) < 300 {
+		w := Word{}
+		for i := range w {
+			w[i] = rune("aeirst"[rng.Intn(6)])
+		}
+		dict = append(dict, w)
+	}
+	for _, scoring := range []Scoring{Classic, Peaks} {
+		for trial := 0; trial < 50; trial++ {
+			answer := dict[rng.Intn(len(dict))]
+			results := ResultSet{}
+			for i := 0; i < 1+trial%3; i++ {
+				guess := dict[rng.Intn(len(dict))]
+				results = append(results, Result{Word: guess, Pattern: scoring.Check(answer, guess)})
+			}
+			c, err := NewConstraints(results, Latin)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, w := range dict {
+				consistent := true
+				for _, r := range results {
+					consistent = consistent && scoring.Check(w, r.Word) == r.Pattern
+				}
+				if c.Matches(w) != consistent {
+					t.Fatalf("%s: %s matches %v, gives the history %v\n%s", scoring.Name, w, c.Matches(w), consistent, results)
+				}
+			}
+		}
+	}
+}
+
+func TestConstraints_Summary(t *testing.T) {
+	c, err := NewConstraints(history(Classic, "sheep", "steep"), Latin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Join([]string{
+		"position 1: s",
+		"position 2: not t",
+		"position 3: e",
+		"position 4: e",
+		"position 5: p",
+		"contains: e (at least 2), p, s",
+		"excludes: t",
+	}, "\n")
+	if got := c.String(); got != want {
+		t.Errorf("summary\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestConstraints_Regexp(t *testing.T) {
+	tests := []struct {
+		name    string
+		results ResultSet
+		want    string
+	}{
+		{
+			name:    "counts and positions",
+			results: history(Classic, "sheep", "steep"),
+			want:    "^(?=(?:.*e){2})(?=(?:.*p){1})(?=(?:.*s){1})[s][abcdefghijklmnopqrsuvwxyz][e][e][p]$",
+		},
+		{
+			name: "contradictory history",
+			results: ResultSet{
+				{Word: MakeWord("tares"), Pattern: Pattern{Grey, Grey, Grey, Grey, Grey}},
+				{Word: MakeWord("tares"), Pattern: Pattern{Green, Grey, Grey, Grey, Grey}},
+			},
+			want: "^(?=(?:.*t){1})(?!)[bcdfghijklmnopquvwxyz][bcdfghijklmnopquvwxyz][bcdfghijklmnopquvwxyz][bcdfghijklmnopquvwxyz]$",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewConstraints(tt.results, Latin)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := c.Regexp()
+			if got != tt.want {
+				t.Errorf("Regexp() = %s, want %s", got, tt.want)
+			}
+			if strings.Contains(got, "[]") {
+				t.Errorf("Regexp() has an empty class: %s", got)
+			}
+			// go's regexp has no lookaheads, but the positions alone must compile
+			positions := regexp.MustCompile(`\(\?[=!][^)]*\)[^)]*\)|\(\?[=!]\)`).ReplaceAllString(got, "")
+			if _, err := regexp.Compile(positions); err != nil {
+				t.Errorf("%s doesn't compile: %v", positions, err)
+			}
+		})
+	}
+}
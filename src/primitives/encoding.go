@@ -0,0 +1,35 @@
+package primitives
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// MarshalText writes the word as its letters, so it is a plain string in json
+func (f Word) MarshalText() ([]byte, error) {
+	return []byte(f.String()), nil
+}
+
+// UnmarshalText reads a word written by MarshalText
+func (f *Word) UnmarshalText(text []byte) error {
+	if n := utf8.RuneCount(text); n != WordLength {
+		return fmt.Errorf("%q has %d letters, expected %d", text, n, WordLength)
+	}
+	*f = MakeWord(string(text))
+	return nil
+}
+
+// MarshalText writes the pattern in the LetterNotation, e.g. gy..g
+func (p Pattern) MarshalText() ([]byte, error) {
+	return []byte(p.Format(LetterNotation)), nil
+}
+
+// UnmarshalText reads a pattern in any Notation
+func (p *Pattern) UnmarshalText(text []byte) error {
+	parsed, err := ParsePattern(string(text))
+	if err != nil {
+		return err
+	}
+	*p = parsed
+	return nil
+}
@@ -0,0 +1,193 @@
+package primitives
+
+import (
+	"github.com/fatih/color"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// The seed corpus of each target is in testdata/fuzz, run e.g.
+//
+//	go test ./src/primitives -fuzz FuzzCheckGuess
+//
+// to search for more inputs, failures are added to the corpus to be checked from then on.
+
+func FuzzMakeWord(f *testing.F) {
+	f.Fuzz(func(t *testing.T, s string) {
+		w := MakeWord(s)
+
+		// the first WordLength runes are kept, nulls are dropped when it is written out
+		runes := []rune(s)
+		if len(runes) > WordLength {
+			runes = runes[:WordLength]
+		}
+		want := strings.ReplaceAll(string(runes), "\x00", "")
+		if got := w.String(); got != want {
+			t.Fatalf("MakeWord(%q).String() = %q, want %q", s, got, want)
+		}
+		if MakeWord(w.String()) != w && !strings.ContainsRune(string(runes), 0) {
+			t.Fatalf("MakeWord(%q) doesn't survive a round trip through String", s)
+		}
+
+		// only whole words can be read back from text
+		text, _ := w.MarshalText()
+		var back Word
+		err := back.UnmarshalText(text)
+		if whole := utf8.RuneCount(text) == WordLength; whole != (err == nil) {
+			t.Fatalf("UnmarshalText(%q) gave %v", text, err)
+		} else if whole && back != w {
+			t.Fatalf("UnmarshalText(%q) = %v, want %v", text, back, w)
+		}
+	})
+}
+
+func FuzzPatternByte(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b byte) {
+		b %= PatternCardinality
+		p := PatternFrom(b)
+
+		// Sum is int8 so wraps round above 127, Byte must undo that
+		if p.Byte() != b {
+			t.Fatalf("PatternFrom(%d).Byte() = %d", b, p.Byte())
+		}
+		if byte(p.Sum()) != b {
+			t.Fatalf("PatternFrom(%d).Sum() = %d", b, p.Sum())
+		}
+		if PatternFrom(int(b)) != p || PatternIndex[b] != p {
+			t.Fatalf("PatternFrom(%d) depends on the type of its argument", b)
+		}
+		if Classic.Decode(b) != p {
+			t.Fatalf("Classic.Decode(%d) = %v, want %v", b, Classic.Decode(b), p)
+		}
+		peaks := Peaks.Decode(b)
+		if peaks.Byte() != b {
+			t.Fatalf("Peaks.Decode(%d).Byte() = %d", b, peaks.Byte())
+		}
+		for n := range tiles {
+			for _, p := range []Pattern{p, peaks} {
+				parsed, err := ParsePattern(p.Format(n))
+				if err != nil || parsed != p {
+					t.Fatalf("ParsePattern(%q) = %v, %v, want %v", p.Format(n), parsed, err, p)
+				}
+			}
+		}
+	})
+}
+
+func FuzzCheckGuess(f *testing.F) {
+	f.Fuzz(func(t *testing.T, a, g string) {
+		ans, guess := MakeWord(a), MakeWord(g)
+
+		if ans.CheckGuess(ans) != Win {
+			t.Fatalf("%s scored against itself isn't a win", ans)
+		}
+		p := ans.CheckGuess(guess)
+		if (p == Win) != (ans == guess) {
+			t.Fatalf("%s against %s is %v", guess, ans, p)
+		}
+
+		colours := map[rune]Color{}
+		for i, color := range p {
+			switch color {
+			case Green:
+				if guess[i] != ans[i] {
+					t.Fatalf("%s against %s: position %d is green but the letters differ", guess, ans, i)
+				}
+			case Yellow:
+				if guess[i] == ans[i] || !ans.Contains(guess[i]) {
+					t.Fatalf("%s against %s: position %d is yellow", guess, ans, i)
+				}
+			case Grey:
+				if ans.Contains(guess[i]) {
+					t.Fatalf("%s against %s: position %d is grey but %q is in the answer", guess, ans, i, guess[i])
+				}
+			default:
+				t.Fatalf("%s against %s: position %d has colour %d", guess, ans, i, color)
+			}
+
+			// the classic scoring deliberately ignores letter counts: a letter is yellow
+			// wherever it isn't green, however many times it appears in either word, so
+			// repeats of a letter are all coloured alike
+			if color == Green {
+				continue
+			}
+			if previous, ok := colours[guess[i]]; ok && previous != color {
+				t.Fatalf("%s against %s: the repeats of %q are coloured differently", guess, ans, guess[i])
+			}
+			colours[guess[i]] = color
+		}
+
+		// scoring repeats, the green and yellow tiles of each letter are as many as it appears
+		// in both words, so never more than the answer has
+		repeats := DefPattern
+		ScoreRepeats(ans[:], guess[:], repeats[:])
+		inAnswer, inGuess, coloured := map[rune]int{}, map[rune]int{}, map[rune]int{}
+		for i := range ans {
+			inAnswer[ans[i]]++
+			inGuess[guess[i]]++
+			if repeats[i] == Green || repeats[i] == Yellow {
+				coloured[guess[i]]++
+			}
+			if (repeats[i] == Green) != (guess[i] == ans[i]) {
+				t.Fatalf("%s against %s scoring repeats: position %d is %d", guess, ans, i, repeats[i])
+			}
+		}
+		for letter, n := range inGuess {
+			want := n
+			if inAnswer[letter] < want {
+				want = inAnswer[letter]
+			}
+			if coloured[letter] != want {
+				t.Fatalf("%s against %s scoring repeats: %q is coloured %d times, the answer has %d", guess, ans, letter, coloured[letter], inAnswer[letter])
+			}
+		}
+
+		// the constraints from the result always admit the answer
+		if Latin.Valid(ans) && Latin.Valid(guess) {
+			c, err := NewConstraints(ResultSet{{Word: guess, Pattern: p}}, Latin)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !c.Matches(ans) {
+				t.Fatalf("%s against %s: the constraints reject the answer: %v", guess, ans, c.Explain(ans))
+			}
+			peaks := Peaks.Check(ans, guess)
+			if peaks == Win != (ans == guess) {
+				t.Fatalf("%s against %s is %v with peaks scoring", guess, ans, peaks)
+			}
+			if c, err = NewConstraints(ResultSet{{Word: guess, Pattern: peaks}}, Latin); err != nil {
+				t.Fatal(err)
+			}
+			if !c.Matches(ans) {
+				t.Fatalf("%s against %s: the peaks constraints reject the answer: %v", guess, ans, c.Explain(ans))
+			}
+		}
+	})
+}
+
+func FuzzParsePattern(f *testing.F) {
+	// String only paints the tiles when colour is on, which it isn't when testing
+	noColor := color.NoColor
+	color.NoColor = false
+	defer func() { color.NoColor = noColor }()
+
+	f.Fuzz(func(t *testing.T, s string) {
+		p, err := ParsePattern(s)
+		if err != nil {
+			return
+		}
+		for n := range tiles {
+			back, err := ParsePattern(p.Format(n))
+			if err != nil || back != p {
+				t.Fatalf("ParsePattern(%q) = %v, but %q parses as %v, %v", s, p, p.Format(n), back, err)
+			}
+		}
+		if _, err := ParsePattern(p.String()); err != nil {
+			t.Fatalf("ParsePattern(%q) = %v, which can't be read back from its String: %v", s, p, err)
+		}
+		if _, err := ParseResult("tares:" + p.Format(LetterNotation)); err != nil {
+			t.Fatalf("ParseResult with the pattern %v: %v", p, err)
+		}
+	})
+}
@@ -0,0 +1,164 @@
+package primitives
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Notation is a plain text way of writing down a Pattern
+type Notation int
+
+const (
+	// LetterNotation writes gy..g, g is green, y is yellow and . is grey
+	LetterNotation Notation = iota
+	// CapitalNotation writes GY__G
+	CapitalNotation
+	// DigitNotation writes 21002, the colour of each position in turn as 2, 1 or 0, and the
+	// peaks colours Earlier and Later as 3 and 4
+	DigitNotation
+	// EmojiNotation writes 🟩🟨⬛⬛🟩, the squares of a dark mode share grid
+	EmojiNotation
+	// LightEmojiNotation writes 🟩🟨⬜⬜🟩, the squares of a light mode share grid
+	LightEmojiNotation
+	// HighContrastEmojiNotation writes 🟧🟦⬛⬛🟧, orange for green and blue for yellow
+	HighContrastEmojiNotation
+)
+
+// Notations is the lookup of the notations by name
+var Notations = map[string]Notation{
+	"letters":       LetterNotation,
+	"capitals":      CapitalNotation,
+	"digits":        DigitNotation,
+	"emoji":         EmojiNotation,
+	"light":         LightEmojiNotation,
+	"high-contrast": HighContrastEmojiNotation,
+}
+
+// tiles is the symbol for Grey, Yellow, Green, Earlier and Later in each notation
+var tiles = map[Notation][5]string{
+	LetterNotation:            {".", "y", "g", "<", ">"},
+	CapitalNotation:           {"_", "Y", "G", "<", ">"},
+	DigitNotation:             {"0", "1", "2", "3", "4"},
+	EmojiNotation:             {"⬛", "🟨", "🟩", "🔽", "🔼"},
+	LightEmojiNotation:        {"⬜", "🟨", "🟩", "🔽", "🔼"},
+	HighContrastEmojiNotation: {"⬛", "🟦", "🟧", "🔽", "🔼"},
+}
+
+// tileColors is every symbol ParsePattern understands. The peaks colours are written as
+// < and > in the letter notations, 3 and 4 in digits and as down and up arrows in emoji.
+var tileColors = map[rune]Color{
+	'.': Grey, '_': Grey, '-': Grey, 'x': Grey, 'X': Grey, 'b': Grey, 'B': Grey, '0': Grey,
+	'⬛': Grey, '⬜': Grey,
+	'y': Yellow, 'Y': Yellow, '1': Yellow, '🟨': Yellow, '🟦': Yellow,
+	'g': Green, 'G': Green, '2': Green, '🟩': Green, '🟧': Green,
+	'<': Earlier, '3': Earlier, '🔽': Earlier,
+	'>': Later, '4': Later, '🔼': Later,
+}
+
+// Format writes the pattern in the notation, each colour has its own symbol so the pattern
+// can be read back by ParsePattern
+func (p Pattern) Format(n Notation) string {
+	symbols, ok := tiles[n]
+	if !ok {
+		symbols = tiles[LetterNotation]
+	}
+	s := ""
+	for _, color := range p {
+		switch color {
+		case Green:
+			s += symbols[2]
+		case Yellow:
+			s += symbols[1]
+		case Earlier:
+			s += symbols[3]
+		case Later:
+			s += symbols[4]
+		default:
+			s += symbols[0]
+		}
+	}
+
+	return s
+}
+
+// ansiTile matches a single tile painted by Color.Paint, capturing the sgr parameters
+var ansiTile = regexp.MustCompile("\x1b\\[([0-9;]*)m[^\x1b]*\x1b\\[0m")
+
+// ParsePattern reads a pattern written in any Notation, or painted by Pattern.String or
+// Result.String with colour output on. Variation selectors and white space are ignored.
+func ParsePattern(s string) (Pattern, error) {
+	if strings.Contains(s, "\x1b[") {
+		return parseANSI(s)
+	}
+
+	p, i := Pattern{}, 0
+	for _, r := range s {
+		if unicode.IsSpace(r) || r == '\ufe0f' {
+			continue
+		}
+		color, ok := tileColors[r]
+		if !ok {
+			return p, fmt.Errorf("%q in %q is not a pattern tile", r, s)
+		}
+		if i >= len(p) {
+			return p, fmt.Errorf("%q has more than %d tiles", s, len(p))
+		}
+		p[i] = color
+		i++
+	}
+	if i != len(p) {
+		return p, fmt.Errorf("%q has %d tiles, expected %d", s, i, len(p))
+	}
+
+	return p, nil
+}
+
+// parseANSI reads the background colour of each painted tile
+func parseANSI(s string) (Pattern, error) {
+	p := Pattern{}
+	matches := ansiTile.FindAllStringSubmatch(s, -1)
+	if len(matches) != len(p) {
+		return p, fmt.Errorf("found %d painted tiles, expected %d", len(matches), len(p))
+	}
+	for i, match := range matches {
+		found := false
+		for _, param := range strings.Split(match[1], ";") {
+			code, err := strconv.Atoi(param)
+			if err != nil {
+				continue
+			}
+			switch Color(code) {
+			case Grey, Yellow, Green, Earlier, Later:
+				p[i], found = Color(code), true
+			}
+		}
+		if !found {
+			return p, fmt.Errorf("tile %d has no pattern colour", i+1)
+		}
+	}
+
+	return p, nil
+}
+
+// ParseResult reads a guess and its pattern written as "word pattern", "word:pattern" or
+// "word=pattern", e.g. tares:gy..g
+func ParseResult(s string) (Result, error) {
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ':' || r == '=' || unicode.IsSpace(r)
+	})
+	if len(fields) != 2 {
+		return Result{}, fmt.Errorf("%q is not a word and a pattern", s)
+	}
+	if len([]rune(fields[0])) != WordLength {
+		return Result{}, fmt.Errorf("%q is not %d letters long", fields[0], WordLength)
+	}
+	p, err := ParsePattern(fields[1])
+	if err != nil {
+		return Result{}, err
+	}
+
+	return Result{Word: MakeWord(fields[0]), Pattern: p}, nil
+}
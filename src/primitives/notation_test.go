@@ -0,0 +1,74 @@
+package primitives
+
+import (
+	"github.com/fatih/color"
+	"testing"
+)
+
+func TestPattern_FormatParse(t *testing.T) {
+	// String only paints the tiles when colour is on, which it isn't when testing
+	noColor := color.NoColor
+	color.NoColor = false
+	defer func() { color.NoColor = noColor }()
+
+	for _, scoring := range []Scoring{Classic, Peaks} {
+		for b := 0; b < PatternCardinality; b++ {
+			p := scoring.Decode(byte(b))
+			for n := range tiles {
+				back, err := ParsePattern(p.Format(n))
+				if err != nil || back != p {
+					t.Fatalf("%s: %q parses as %v, %v, want %v", scoring.Name, p.Format(n), back, err, p)
+				}
+			}
+			if back, err := ParsePattern(p.String()); err != nil || back != p {
+				t.Fatalf("%s: the painted %v parses as %v, %v", scoring.Name, p, back, err)
+			}
+		}
+	}
+}
+
+func TestPattern_Format(t *testing.T) {
+	p := Pattern{Green, Yellow, Grey, Earlier, Later}
+	tests := []struct {
+		notation Notation
+		want     string
+	}{
+		{LetterNotation, "gy.<>"},
+		{CapitalNotation, "GY_<>"},
+		{DigitNotation, "21034"},
+		{EmojiNotation, "🟩🟨⬛🔽🔼"},
+		{LightEmojiNotation, "🟩🟨⬜🔽🔼"},
+		{HighContrastEmojiNotation, "🟧🟦⬛🔽🔼"},
+	}
+	for _, tt := range tests {
+		if got := p.Format(tt.notation); got != tt.want {
+			t.Errorf("notation %d wrote %q, want %q", tt.notation, got, tt.want)
+		}
+	}
+}
+
+func TestParsePattern_Errors(t *testing.T) {
+	for _, s := range []string{"", "gy.g", "gy..gg", "gy.zg", "21005", "\x1b[42m t \x1b[0m"} {
+		if p, err := ParsePattern(s); err == nil {
+			t.Errorf("%q parsed as %v", s, p)
+		}
+	}
+	// white space and variation selectors are ignored
+	if p, err := ParsePattern(" g y . ⬛️ 🟩 "); err != nil || p != (Pattern{Green, Yellow, Grey, Grey, Green}) {
+		t.Errorf("spaced pattern parsed as %v, %v", p, err)
+	}
+}
+
+func TestParseResult(t *testing.T) {
+	for _, s := range []string{"tares:gy..g", "tares=gy..g", "tares gy..g", "tares 21002"} {
+		r, err := ParseResult(s)
+		if err != nil || r.Word != MakeWord("tares") || r.Pattern != (Pattern{Green, Yellow, Grey, Grey, Green}) {
+			t.Errorf("%q parsed as %v, %v", s, r, err)
+		}
+	}
+	for _, s := range []string{"tares", "tare:gy..g", "tares:gy..", "tares:gy..g:extra"} {
+		if r, err := ParseResult(s); err == nil {
+			t.Errorf("%q parsed as %v", s, r)
+		}
+	}
+}
@@ -6,8 +6,15 @@ import "github.com/fatih/color"
 type Color color.Attribute
 
 // Paint is a convenience wrapper for coloring strings
-func (c Color) Paint(b byte) string {
-	s := string([]byte{b})
+func (c Color) Paint(r rune) string {
+	s := string(r)
+	switch c {
+	case Earlier, Later:
+		// the peaks colours are dark, so the letter is drawn in white, followed by the
+		// direction of the answer's letter so the tile still reads without colour
+		direction := map[Color]string{Earlier: "<", Later: ">"}[c]
+		return color.New(color.Attribute(c), color.FgHiWhite, color.Bold).SprintfFunc()("%s%s", s, direction)
+	}
 	return color.New(color.Attribute(c), color.FgBlack, color.Faint).SprintfFunc()("%s", s)
 }
 
@@ -21,6 +28,14 @@ const (
 	Green = Color(color.BgGreen)
 )
 
+// the wordle peaks colors mean:
+const (
+	// Earlier - the answer's letter comes before the guessed letter in the alphabet
+	Earlier = Color(color.BgBlue)
+	// Later - the answer's letter comes after the guessed letter in the alphabet
+	Later = Color(color.BgMagenta)
+)
+
 const PatternCardinality = 243
 
 type PatternSpace [PatternCardinality]Pattern
@@ -34,7 +49,7 @@ var PatternIndex = func() (pIndex PatternSpace) {
 }()
 
 // Pattern is the letter Result
-type Pattern [5]Color
+type Pattern [WordLength]Color
 
 func PatternFrom[T int | byte](i T) Pattern {
 	p := DefPattern
@@ -57,7 +72,7 @@ func (p Pattern) Sum() (s int8) {
 		switch color {
 		case Green:
 			digit = 2
-		case Yellow:
+		case Yellow, Later:
 			digit = 1
 		default:
 			digit = 0
@@ -0,0 +1,74 @@
+package primitives
+
+// Scoring is a feedback model, it decides how each letter of a guess is coloured when
+// compared to the answer. Every model produces three colours, so patterns share the same
+// base 3 byte encoding and cache layout whichever model built them.
+type Scoring struct {
+	Name string
+	// Check returns the pattern for the guess when ans is the answer
+	Check func(ans, guess Word) Pattern
+	// Palette is the colour of each digit of the base 3 encoding
+	Palette [3]Color
+}
+
+// Classic is the green/yellow/grey feedback of wordle
+var Classic = Scoring{
+	Name:    "classic",
+	Check:   Word.CheckGuess,
+	Palette: [3]Color{Grey, Yellow, Green},
+}
+
+// Peaks is the wordle peaks feedback over the latin alphabet
+var Peaks = PeaksScoring(Latin)
+
+// Scorings is the lookup of the feedback models by name
+var Scorings = map[string]Scoring{
+	Classic.Name: Classic,
+	Peaks.Name:   Peaks,
+}
+
+// PeaksScoring is the wordle peaks feedback model where each letter is either correct, or
+// tells you whether the answer's letter is earlier or later in the alphabet.
+func PeaksScoring(alphabet *Alphabet) Scoring {
+	return Scoring{
+		Name: "peaks",
+		Check: func(ans, guess Word) Pattern {
+			return ans.CheckPeaks(guess, alphabet)
+		},
+		Palette: [3]Color{Earlier, Later, Green},
+	}
+}
+
+// Decode is PatternFrom using the colours of the scoring's palette
+func (s Scoring) Decode(b byte) Pattern {
+	p := PatternFrom(b)
+	for i, color := range p {
+		switch color {
+		case Green:
+			p[i] = s.Palette[2]
+		case Yellow:
+			p[i] = s.Palette[1]
+		default:
+			p[i] = s.Palette[0]
+		}
+	}
+
+	return p
+}
+
+// CheckPeaks returns the wordle peaks Pattern when a guess is compared to the answer f
+func (f Word) CheckPeaks(guess Word, alphabet *Alphabet) Pattern {
+	p := Pattern{}
+	for i := range p {
+		switch {
+		case guess[i] == f[i]:
+			p[i] = Green
+		case alphabet.Less(f[i], guess[i]):
+			p[i] = Earlier
+		default:
+			p[i] = Later
+		}
+	}
+
+	return p
+}
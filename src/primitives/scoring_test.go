@@ -0,0 +1,54 @@
+package primitives
+
+import "testing"
+
+func TestWord_CheckPeaks(t *testing.T) {
+	const (
+		G = Green
+		E = Earlier
+		L = Later
+	)
+	tests := []struct {
+		alphabet      *Alphabet
+		answer, guess string
+		want          Pattern
+	}{
+		{Latin, "light", "light", Pattern{G, G, G, G, G}},
+		{Latin, "light", "tares", Pattern{E, L, E, L, L}},
+		{Latin, "light", "night", Pattern{E, G, G, G, G}},
+		{Latin, "aaaaa", "zzzzz", Pattern{E, E, E, E, E}},
+		{Latin, "zzzzz", "aaaaa", Pattern{L, L, L, L, L}},
+		// only the answer's letter at the same position counts, not the rest of the word
+		{Latin, "abcde", "edcba", Pattern{E, E, G, L, L}},
+		// ñ comes between n and o in spanish, but after every latin letter by code point
+		{Spanish, "ñandu", "nandu", Pattern{L, G, G, G, G}},
+		{Spanish, "ñandu", "oandu", Pattern{E, G, G, G, G}},
+		{Latin, "ñandu", "oandu", Pattern{L, G, G, G, G}},
+		{Digits, "10007", "20003", Pattern{E, G, G, G, L}},
+	}
+	for _, tt := range tests {
+		got := MakeWord(tt.answer).CheckPeaks(MakeWord(tt.guess), tt.alphabet)
+		if got != tt.want {
+			t.Errorf("%s: %s against %s is %s, want %s", tt.alphabet.Name, tt.guess, tt.answer, got.Format(LetterNotation), tt.want.Format(LetterNotation))
+		}
+		if scored := PeaksScoring(tt.alphabet).Check(MakeWord(tt.answer), MakeWord(tt.guess)); scored != got {
+			t.Errorf("%s: the peaks scoring of %s against %s differs from CheckPeaks", tt.alphabet.Name, tt.guess, tt.answer)
+		}
+	}
+}
+
+func TestScoring_Decode(t *testing.T) {
+	for _, scoring := range []Scoring{Classic, Peaks} {
+		for b := 0; b < PatternCardinality; b++ {
+			p := scoring.Decode(byte(b))
+			if p.Byte() != byte(b) {
+				t.Errorf("%s: %d decoded as %s which encodes as %d", scoring.Name, b, p.Format(LetterNotation), p.Byte())
+			}
+			for _, color := range p {
+				if color != scoring.Palette[0] && color != scoring.Palette[1] && color != scoring.Palette[2] {
+					t.Errorf("%s: %d decoded with a colour outside the palette", scoring.Name, b)
+				}
+			}
+		}
+	}
+}
@@ -6,28 +6,42 @@ import (
 	"io/ioutil"
 	"log"
 	"os"
+	"unicode/utf8"
 )
 
+// WordLength is the number of letters in a Word
+const WordLength = 5
+
 // Word is a Word with five letters
-type Word [5]byte
+type Word [WordLength]rune
 
-// MakeWord uses the first five bytes of the input string to populate a fivegram,
-// if there are less than five, the remaining bytes are null
+// MakeWord uses the first five runes of the input string to populate a fivegram,
+// if there are less than five, the remaining runes are null
 func MakeWord(s string) Word {
 	res := Word{}
-	input := []byte(s)
-	if len(input) < 5 {
-		input = append(input, make([]byte, 5-len(input))...)
-	}
-	for i, b := range input {
-		res[i] = b
+	for i, r := range []rune(s) {
+		if i >= len(res) {
+			break
+		}
+		res[i] = r
 	}
 
 	return res
 }
 
+// String renders the word as text, null letters are dropped
+func (f Word) String() string {
+	s := make([]rune, 0, len(f))
+	for _, r := range f {
+		if r != 0 {
+			s = append(s, r)
+		}
+	}
+	return string(s)
+}
+
 // Contains returns true if the Word Contains the character
-func (f Word) Contains(character byte) bool {
+func (f Word) Contains(character rune) bool {
 	for _, letter := range f {
 		if character == letter {
 			return true
@@ -36,19 +50,53 @@ func (f Word) Contains(character byte) bool {
 	return false
 }
 
-// CheckGuess returns the Pattern when a guess is compared to any other Word
+// CheckGuess returns the Pattern when a guess is compared to any other Word. Letter counts
+// are ignored: a letter is yellow wherever it isn't green if it appears anywhere in the
+// answer, so a repeated letter can be coloured more times than the answer has it. See
+// ScoreRepeats for the scoring that counts them.
 func (f Word) CheckGuess(guess Word) Pattern {
 	p := DefPattern
+	Score(f[:], guess[:], p[:])
+
+	return p
+}
+
+// Score is the length agnostic implementation of CheckGuess, it writes the colour of each
+// guess letter when compared to the answer into p, which must be as long as the guess.
+func Score(ans, guess []rune, p []Color) {
 	for i := range p {
-		if f.Contains(guess[i]) {
-			p[i] = Yellow
+		p[i] = Grey
+		for _, letter := range ans {
+			if letter == guess[i] {
+				p[i] = Yellow
+				break
+			}
 		}
-		if guess[i] == f[i] {
+		if guess[i] == ans[i] {
 			p[i] = Green
 		}
 	}
+}
 
-	return p
+// ScoreRepeats is Score where a repeated letter is coloured no more times than it appears
+// in the answer: greens are taken first, then yellows from left to right, and any further
+// copies of the letter in the guess are grey.
+func ScoreRepeats(ans, guess []rune, p []Color) {
+	unmatched := map[rune]int{}
+	for i := range p {
+		p[i] = Grey
+		if guess[i] == ans[i] {
+			p[i] = Green
+			continue
+		}
+		unmatched[ans[i]]++
+	}
+	for i := range p {
+		if p[i] != Green && unmatched[guess[i]] > 0 {
+			p[i] = Yellow
+			unmatched[guess[i]]--
+		}
+	}
 }
 
 // Dictionary is a collection of Fivegrams
@@ -56,24 +104,38 @@ type Dictionary []Word
 
 // LoadWords pulls the content of the words file into memory
 func LoadWords() (dict Dictionary) {
-	file, err := os.Open("./data/words")
+	dict, err := LoadWordsFrom("./data/words", Latin)
 	if err != nil {
 		log.Fatal(err)
 	}
+
+	return dict
+}
+
+// LoadWordsFrom reads a utf-8 encoded words file, one word per line, keeping the lines
+// that are exactly five letters of the alphabet long.
+func LoadWordsFrom(path string, alphabet *Alphabet) (dict Dictionary, err error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
 	content, err := ioutil.ReadAll(file)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 
 	lines := bytes.Split(content, []byte{'\n'})
 
 	for _, line := range lines {
-		if len(line) != 5 {
+		line = bytes.TrimSuffix(line, []byte{'\r'})
+		if utf8.RuneCount(line) != WordLength {
 			continue
 		}
-		var word Word
-		for i := range word {
-			word[i] = line[i]
+		word := MakeWord(string(line))
+		if !alphabet.Valid(word) {
+			continue
 		}
 
 		dict = append(
@@ -82,7 +144,7 @@ func LoadWords() (dict Dictionary) {
 		)
 	}
 
-	return dict
+	return dict, nil
 }
 
 // IndexOf returns the index of a Word in the Dictionary
@@ -98,18 +160,16 @@ func (d Dictionary) IndexOf(word Word) (idx int, ok bool) {
 
 // Result is the word and its corresponding pattern as compared to some input
 type Result struct {
-	Word    Word
-	Pattern Pattern
+	Word    Word    `json:"word"`
+	Pattern Pattern `json:"pattern"`
 }
 
 func (r Result) String() string {
 	s := ""
 	for i, color := range r.Pattern {
 		switch color {
-		case Green:
-			s += Green.Paint(r.Word[i])
-		case Yellow:
-			s += Yellow.Paint(r.Word[i])
+		case Green, Yellow, Earlier, Later:
+			s += color.Paint(r.Word[i])
 		default:
 			s += Grey.Paint(r.Word[i])
 		}
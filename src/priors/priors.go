@@ -0,0 +1,137 @@
+// Package priors turns word frequency data into the probability of each word being the answer.
+package priors
+
+import (
+	"bit-wordy/src/primitives"
+	"bufio"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DefaultCentre is the frequency rank at which a word is as likely as not to be an answer
+	DefaultCentre = 3000
+	// DefaultWidth is the number of ranks over which the prior falls from ~0.73 to ~0.27
+	DefaultWidth = 500
+)
+
+// Frequencies is the number of occurrences of each word in some corpus
+type Frequencies map[primitives.Word]float64
+
+// LoadFrequencies reads a file of "word count" lines, separated by whitespace or a comma.
+// Blank lines and lines starting with # are skipped.
+func LoadFrequencies(path string) (Frequencies, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	freqs := Frequencies{}
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.FieldsFunc(text, func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\t'
+		})
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("%s:%d: expected a word and a count, got %q", path, line, text)
+		}
+		count, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		freqs[primitives.MakeWord(fields[0])] += count
+	}
+
+	return freqs, scanner.Err()
+}
+
+// Prior is the weight of each word of a dictionary, indexed like the dictionary. The
+// weights are relative, they don't need to sum to one.
+type Prior []float64
+
+// Uniform is the prior that makes every word equally likely
+func Uniform(dict primitives.Dictionary) Prior {
+	prior := make(Prior, len(dict))
+	for i := range prior {
+		prior[i] = 1
+	}
+
+	return prior
+}
+
+// Sigmoid ranks the words of the dictionary from most to least frequent and weights them by
+// a logistic curve over the rank, so common words are close to 1 and rare ones fall off to 0
+// past the centre rank. Words absent from the frequencies rank last. Words with the same
+// frequency, and all the absent words, share the average of the ranks they span, so they
+// weigh the same whatever their spelling or the dictionary order.
+func Sigmoid(freqs Frequencies, dict primitives.Dictionary, centre, width float64) Prior {
+	order := make([]int, len(dict))
+	for i := range order {
+		order[i] = i
+	}
+	// before is whether word a ranks above word b, false for both orders when they tie
+	before := func(a, b primitives.Word) bool {
+		freqA, knownA := freqs[a]
+		freqB, knownB := freqs[b]
+		if knownA != knownB {
+			return knownA
+		}
+		return knownA && freqA > freqB
+	}
+	sort.Slice(order, func(i, j int) bool { return before(dict[order[i]], dict[order[j]]) })
+
+	prior := make(Prior, len(dict))
+	for first := 0; first < len(order); {
+		last := first
+		for last+1 < len(order) && !before(dict[order[first]], dict[order[last+1]]) {
+			last++
+		}
+		rank := float64(first+last) / 2
+		for _, wordId := range order[first : last+1] {
+			prior[wordId] = 1 / (1 + math.Exp((rank-centre)/width))
+		}
+		first = last + 1
+	}
+
+	return prior
+}
+
+// Sampler draws words from a dictionary with probability proportional to a prior
+type Sampler struct {
+	dict       primitives.Dictionary
+	cumulative []float64
+	rng        *rand.Rand
+}
+
+// NewSampler returns a sampler over the dictionary using the random source
+func NewSampler(dict primitives.Dictionary, prior Prior, rng *rand.Rand) *Sampler {
+	cumulative := make([]float64, len(prior))
+	total := 0.0
+	for i, weight := range prior {
+		total += weight
+		cumulative[i] = total
+	}
+
+	return &Sampler{dict: dict, cumulative: cumulative, rng: rng}
+}
+
+// Sample returns a random word
+func (s *Sampler) Sample() primitives.Word {
+	total := s.cumulative[len(s.cumulative)-1]
+	i := sort.SearchFloat64s(s.cumulative, s.rng.Float64()*total)
+	if i >= len(s.dict) {
+		i = len(s.dict) - 1
+	}
+
+	return s.dict[i]
+}
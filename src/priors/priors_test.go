@@ -0,0 +1,104 @@
+package priors
+
+import (
+	"bit-wordy/src/primitives"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func dictionary(words ...string) primitives.Dictionary {
+	dict := primitives.Dictionary{}
+	for _, w := range words {
+		dict = append(dict, primitives.MakeWord(w))
+	}
+	return dict
+}
+
+func TestLoadFrequencies(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "freqs")
+	content := "# word counts\nlight 10\n\nmight,4\nlight\t2\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	freqs, err := LoadFrequencies(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if freqs[primitives.MakeWord("light")] != 12 || freqs[primitives.MakeWord("might")] != 4 {
+		t.Errorf("loaded %v", freqs)
+	}
+
+	if err = os.WriteFile(path, []byte("light ten\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = LoadFrequencies(path); err == nil {
+		t.Error("a count that isn't a number loaded")
+	}
+}
+
+// TestSigmoid_Ranks checks the ranks follow the frequencies with the unknown words last, and
+// that tied words weigh the same, whatever the dictionary order
+func TestSigmoid_Ranks(t *testing.T) {
+	freqs := Frequencies{
+		primitives.MakeWord("crane"): 50,
+		primitives.MakeWord("light"): 10,
+		primitives.MakeWord("might"): 10,
+		primitives.MakeWord("zesty"): 0,
+	}
+	// the weights fall by rank, so sorting by weight gives the ranking, each group ties
+	want := [][]string{{"crane"}, {"light", "might"}, {"zesty"}, {"aback", "batch", "match"}}
+	orders := []primitives.Dictionary{
+		dictionary("match", "might", "zesty", "aback", "light", "batch", "crane"),
+		dictionary("aback", "batch", "crane", "light", "match", "might", "zesty"),
+	}
+	for _, dict := range orders {
+		prior := Sigmoid(freqs, dict, 2, 1)
+		weights := map[string]float64{}
+		for i, w := range dict {
+			weights[w.String()] = prior[i]
+		}
+		for rank, group := range want {
+			for _, w := range group[1:] {
+				if weights[w] != weights[group[0]] {
+					t.Errorf("dictionary %v: %s weighs %f but tied %s weighs %f", dict, w, weights[w], group[0], weights[group[0]])
+				}
+			}
+			if rank > 0 && weights[want[rank-1][0]] <= weights[group[0]] {
+				t.Errorf("dictionary %v: %s weighs %f, no more than %s at %f",
+					dict, want[rank-1][0], weights[want[rank-1][0]], group[0], weights[group[0]])
+			}
+		}
+	}
+
+	// a tie shares the average of its ranks, light and might span ranks 1 and 2
+	prior := Sigmoid(freqs, orders[1], 2, 1)
+	if want := 1 / (1 + math.Exp(1.5-2)); math.Abs(prior[3]-want) > 1e-12 {
+		t.Errorf("light weighs %f, want %f", prior[3], want)
+	}
+}
+
+func TestSampler_FollowsPrior(t *testing.T) {
+	dict := dictionary("light", "might", "night")
+	s := NewSampler(dict, Prior{3, 1, 0}, rand.New(rand.NewSource(1)))
+	counts := map[primitives.Word]int{}
+	for i := 0; i < 4000; i++ {
+		counts[s.Sample()]++
+	}
+	if counts[dict[2]] != 0 {
+		t.Errorf("sampled a word with no weight %d times", counts[dict[2]])
+	}
+	if ratio := float64(counts[dict[0]]) / float64(counts[dict[1]]); ratio < 2.5 || ratio > 3.5 {
+		t.Errorf("sampled light %.2f times as often as might, want about 3", ratio)
+	}
+}
+
+func TestUniform(t *testing.T) {
+	for _, w := range Uniform(dictionary("light", "might")) {
+		if w != 1 {
+			t.Errorf("uniform weight %f", w)
+		}
+	}
+}
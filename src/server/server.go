@@ -0,0 +1,397 @@
+// Package server exposes the solver as an HTTP JSON API.
+//
+// Every endpoint takes a JSON body by POST:
+//
+//	/suggest     {"history": [{"guess": "tares", "pattern": "gy..g"}]}
+//	/score       {"answer": "light", "guess": "tares"}
+//	/candidates  {"history": [...], "limit": 10}
+//	/solve       {"answer": "light"}
+//
+// With HandleSessions the games can also be played a guess at a time:
+//
+//	/sessions/new    {"answer": "light"}, or {} for a random answer
+//	/sessions/guess  {"id": "...", "guess": "tares"}
+//	/sessions/state  {"id": "..."}
+//	/sessions/hint   {"id": "..."}
+//
+// Patterns can be written in any primitives.Notation. Failures are reported as
+// {"error": "..."} with a 4xx or 5xx status.
+package server
+
+import (
+	"bit-wordy/src/cached"
+	"bit-wordy/src/games"
+	"bit-wordy/src/primitives"
+	"bit-wordy/src/sessions"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"unicode/utf8"
+)
+
+// Server answers solver requests from one set of Patterns, loaded once and shared between
+// requests. Patterns are never modified once built, pruning makes new ones, so no locking
+// is needed.
+type Server struct {
+	// Opener is the first guess suggested and solved with, it must be in the vocabulary
+	Opener   primitives.Word
+	patterns *cached.Patterns
+	scoring  primitives.Scoring
+	strategy cached.Strategy
+	sessions *sessions.Manager
+	mux      *http.ServeMux
+}
+
+// New returns a Server over the patterns
+func New(p *cached.Patterns, scoring primitives.Scoring, strategy cached.Strategy) *Server {
+	s := &Server{
+		Opener:   cached.NewSolver(p).Opener,
+		patterns: p,
+		scoring:  scoring,
+		strategy: strategy,
+		mux:      http.NewServeMux(),
+	}
+	s.mux.HandleFunc("/suggest", post(s.suggest))
+	s.mux.HandleFunc("/score", post(s.score))
+	s.mux.HandleFunc("/candidates", post(s.candidates))
+	s.mux.HandleFunc("/solve", post(s.solve))
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// Error is the body of every failed request
+type Error struct {
+	Status  int    `json:"-"`
+	Message string `json:"error"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func badRequest(format string, a ...any) *Error {
+	return &Error{Status: http.StatusBadRequest, Message: fmt.Sprintf(format, a...)}
+}
+
+// handler decodes the request into its body and returns the response to encode
+type handler[Req any] func(req Req) (any, *Error)
+
+// post adapts a handler to http, only accepting POSTed json
+func post[Req any](h handler[Req]) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			writeJSON(w, http.StatusMethodNotAllowed, &Error{Message: r.Method + " is not allowed, use POST"})
+			return
+		}
+
+		var req Req
+		decoder := json.NewDecoder(r.Body)
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&req); err != nil {
+			writeJSON(w, http.StatusBadRequest, badRequest("invalid json: %s", err))
+			return
+		}
+
+		resp, e := h(req)
+		if e != nil {
+			writeJSON(w, e.Status, e)
+			return
+		}
+		writeJSON(w, http.StatusOK, resp)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// Turn is a guess and the pattern it received
+type Turn struct {
+	Guess   string `json:"guess"`
+	Pattern string `json:"pattern"`
+}
+
+// word validates a word given in a request
+func (s *Server) word(field, w string) (primitives.Word, *Error) {
+	if utf8.RuneCountInString(w) != primitives.WordLength {
+		return primitives.Word{}, badRequest("%s %q is not %d letters long", field, w, primitives.WordLength)
+	}
+	word := primitives.MakeWord(w)
+	if !s.patterns.Contains(word) {
+		return word, badRequest("%s %q is not in the vocabulary", field, w)
+	}
+	return word, nil
+}
+
+// remaining prunes the answers by the history
+func (s *Server) remaining(history []Turn) (*cached.Patterns, *Error) {
+	results := primitives.ResultSet{}
+	for i, turn := range history {
+		guess, e := s.word(fmt.Sprintf("history[%d].guess", i), turn.Guess)
+		if e != nil {
+			return nil, e
+		}
+		pattern, err := primitives.ParsePattern(turn.Pattern)
+		if err != nil {
+			return nil, badRequest("history[%d].pattern: %s", i, err)
+		}
+		results = append(results, primitives.Result{Word: guess, Pattern: pattern})
+	}
+
+	p, err := s.patterns.PruneHistory(results)
+	if err != nil {
+		return nil, &Error{Status: http.StatusUnprocessableEntity, Message: err.Error()}
+	}
+	return p, nil
+}
+
+// SuggestRequest is the body of /suggest
+type SuggestRequest struct {
+	History []Turn `json:"history"`
+}
+
+// SuggestResponse is the next guess the strategy would make
+type SuggestResponse struct {
+	Guess     string  `json:"guess"`
+	Entropy   float64 `json:"entropy"`
+	Remaining int     `json:"remaining"`
+}
+
+func (s *Server) suggest(req SuggestRequest) (any, *Error) {
+	p, e := s.remaining(req.History)
+	if e != nil {
+		return nil, e
+	}
+
+	var (
+		guess   primitives.Word
+		entropy float64
+	)
+	if len(req.History) == 0 {
+		guess = s.Opener
+		entropy, _ = p.EntropyOf(guess)
+	} else {
+		guess, entropy = cached.ChooseWithin(s.strategy, p, games.MaxGuesses-len(req.History))
+	}
+
+	return SuggestResponse{Guess: guess.String(), Entropy: entropy, Remaining: len(p.Vocab)}, nil
+}
+
+// ScoreRequest is the body of /score
+type ScoreRequest struct {
+	Answer string `json:"answer"`
+	Guess  string `json:"guess"`
+}
+
+// ScoreResponse is the pattern of the guess in the letter, digit and emoji notations
+type ScoreResponse struct {
+	Pattern string `json:"pattern"`
+	Digits  string `json:"digits"`
+	Emoji   string `json:"emoji"`
+}
+
+func (s *Server) score(req ScoreRequest) (any, *Error) {
+	answer, e := s.word("answer", req.Answer)
+	if e != nil {
+		return nil, e
+	}
+	guess, e := s.word("guess", req.Guess)
+	if e != nil {
+		return nil, e
+	}
+
+	pattern := s.scoring.Check(answer, guess)
+	return ScoreResponse{
+		Pattern: pattern.Format(primitives.LetterNotation),
+		Digits:  pattern.Format(primitives.DigitNotation),
+		Emoji:   pattern.Format(primitives.EmojiNotation),
+	}, nil
+}
+
+// CandidatesRequest is the body of /candidates, a limit of 0 lists every candidate
+type CandidatesRequest struct {
+	History []Turn `json:"history"`
+	Limit   int    `json:"limit"`
+}
+
+// CandidatesResponse is the remaining possible answers
+type CandidatesResponse struct {
+	Count      int      `json:"count"`
+	Candidates []string `json:"candidates"`
+}
+
+func (s *Server) candidates(req CandidatesRequest) (any, *Error) {
+	if req.Limit < 0 {
+		return nil, badRequest("limit must not be negative")
+	}
+	p, e := s.remaining(req.History)
+	if e != nil {
+		return nil, e
+	}
+
+	resp := CandidatesResponse{Count: len(p.Vocab), Candidates: []string{}}
+	for _, w := range p.Vocab {
+		if req.Limit > 0 && len(resp.Candidates) >= req.Limit {
+			break
+		}
+		resp.Candidates = append(resp.Candidates, w.String())
+	}
+	return resp, nil
+}
+
+// SolveRequest is the body of /solve
+type SolveRequest struct {
+	Answer string `json:"answer"`
+}
+
+// SolveResponse is the game the solver played
+type SolveResponse struct {
+	Answer  string `json:"answer"`
+	Won     bool   `json:"won"`
+	Guesses []Turn `json:"guesses"`
+}
+
+func (s *Server) solve(req SolveRequest) (any, *Error) {
+	answer, e := s.word("answer", req.Answer)
+	if e != nil {
+		return nil, e
+	}
+
+	solver := cached.NewSolver(s.patterns)
+	solver.Strategy = s.strategy
+	if err := solver.SetOpener(s.Opener); err != nil {
+		return nil, &Error{Status: http.StatusInternalServerError, Message: err.Error()}
+	}
+	g, _, err := solver.Solve(games.NewGameWith(answer, s.scoring))
+	if err != nil {
+		return nil, &Error{Status: http.StatusInternalServerError, Message: err.Error()}
+	}
+
+	resp := SolveResponse{Answer: answer.String(), Won: g.IsWon(), Guesses: []Turn{}}
+	for _, result := range g.Results {
+		resp.Guesses = append(resp.Guesses, Turn{
+			Guess:   result.Word.String(),
+			Pattern: result.Pattern.Format(primitives.LetterNotation),
+		})
+	}
+	return resp, nil
+}
+
+// HandleSessions adds the /sessions endpoints, played with the manager
+func (s *Server) HandleSessions(m *sessions.Manager) {
+	s.sessions = m
+	s.mux.HandleFunc("/sessions/new", post(s.newSession))
+	s.mux.HandleFunc("/sessions/guess", post(s.guessSession))
+	s.mux.HandleFunc("/sessions/state", post(s.sessionState))
+	s.mux.HandleFunc("/sessions/hint", post(s.sessionHint))
+}
+
+// sessionError maps the session errors to their status
+func sessionError(err error) *Error {
+	switch {
+	case errors.Is(err, sessions.ErrNotFound):
+		return &Error{Status: http.StatusNotFound, Message: err.Error()}
+	case errors.Is(err, sessions.ErrGameOver):
+		return &Error{Status: http.StatusConflict, Message: err.Error()}
+	case errors.Is(err, sessions.ErrNotInVocab):
+		return badRequest(err.Error())
+	default:
+		return &Error{Status: http.StatusInternalServerError, Message: err.Error()}
+	}
+}
+
+// SessionRequest is the body of /sessions/state and /sessions/hint
+type SessionRequest struct {
+	ID string `json:"id"`
+}
+
+// NewSessionRequest is the body of /sessions/new
+type NewSessionRequest struct {
+	Answer string `json:"answer"`
+}
+
+// SessionGuessRequest is the body of /sessions/guess
+type SessionGuessRequest struct {
+	ID    string `json:"id"`
+	Guess string `json:"guess"`
+}
+
+// SessionResponse is the state of a session, the answer is only given once the game is over
+type SessionResponse struct {
+	ID        string `json:"id"`
+	Guesses   []Turn `json:"guesses"`
+	Remaining int    `json:"remaining"`
+	Won       bool   `json:"won"`
+	Lost      bool   `json:"lost"`
+	Answer    string `json:"answer,omitempty"`
+}
+
+func sessionResponse(state sessions.State) SessionResponse {
+	resp := SessionResponse{
+		ID:        state.ID,
+		Guesses:   []Turn{},
+		Remaining: len(state.Remaining.Vocab),
+		Won:       state.Game.IsWon(),
+		Lost:      state.Game.IsLost(),
+	}
+	for _, result := range state.Game.Results {
+		resp.Guesses = append(resp.Guesses, Turn{
+			Guess:   result.Word.String(),
+			Pattern: result.Pattern.Format(primitives.LetterNotation),
+		})
+	}
+	if state.Over() {
+		resp.Answer = state.Answer
+	}
+	return resp
+}
+
+func (s *Server) newSession(req NewSessionRequest) (any, *Error) {
+	state, err := s.sessions.Create(req.Answer)
+	if err != nil {
+		return nil, sessionError(err)
+	}
+	return sessionResponse(state), nil
+}
+
+func (s *Server) guessSession(req SessionGuessRequest) (any, *Error) {
+	if _, e := s.word("guess", req.Guess); e != nil {
+		return nil, e
+	}
+	state, err := s.sessions.Guess(req.ID, req.Guess)
+	if err != nil {
+		return nil, sessionError(err)
+	}
+	return sessionResponse(state), nil
+}
+
+func (s *Server) sessionState(req SessionRequest) (any, *Error) {
+	state, err := s.sessions.Get(req.ID)
+	if err != nil {
+		return nil, sessionError(err)
+	}
+	return sessionResponse(state), nil
+}
+
+func (s *Server) sessionHint(req SessionRequest) (any, *Error) {
+	hint, err := s.sessions.Hint(req.ID)
+	if err != nil {
+		return nil, sessionError(err)
+	}
+	state, err := s.sessions.Get(req.ID)
+	if err != nil {
+		return nil, sessionError(err)
+	}
+	return SuggestResponse{
+		Guess:     hint.Guess.String(),
+		Entropy:   hint.Entropy,
+		Remaining: len(state.Remaining.Vocab),
+	}, nil
+}
@@ -0,0 +1,164 @@
+package server
+
+import (
+	"bit-wordy/src/cached"
+	"bit-wordy/src/primitives"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+var testWords = []string{
+	"tares", "light", "might", "night", "sight", "fight", "right", "tight",
+	"match", "batch", "catch", "hatch", "latch", "patch", "watch", "crane",
+}
+
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	dict := primitives.Dictionary{}
+	for _, w := range testWords {
+		dict = append(dict, primitives.MakeWord(w))
+	}
+	p := cached.BuildPatterns(dict)
+	ts := httptest.NewServer(New(p, primitives.Classic, cached.Entropy{}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+// call posts the body to the path and decodes the response into resp
+func call(t *testing.T, ts *httptest.Server, path string, body any, resp any) int {
+	t.Helper()
+	content, err := json.Marshal(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := http.Post(ts.URL+path, "application/json", bytes.NewReader(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Body.Close()
+	if err = json.NewDecoder(r.Body).Decode(resp); err != nil {
+		t.Fatalf("decoding %s response: %s", path, err)
+	}
+	return r.StatusCode
+}
+
+func TestServer_Score(t *testing.T) {
+	ts := newTestServer(t)
+	tests := []struct {
+		name       string
+		req        ScoreRequest
+		wantStatus int
+		wantDigits string
+	}{
+		{"win", ScoreRequest{Answer: "light", Guess: "light"}, http.StatusOK, "22222"},
+		{"partial", ScoreRequest{Answer: "light", Guess: "tares"}, http.StatusOK, "10000"},
+		{"too short", ScoreRequest{Answer: "light", Guess: "tar"}, http.StatusBadRequest, ""},
+		{"not a word", ScoreRequest{Answer: "zzzzz", Guess: "tares"}, http.StatusBadRequest, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp struct {
+				ScoreResponse
+				Error string `json:"error"`
+			}
+			status := call(t, ts, "/score", tt.req, &resp)
+			if status != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (%s)", status, tt.wantStatus, resp.Error)
+			}
+			if status == http.StatusOK && resp.Digits != tt.wantDigits {
+				t.Errorf("digits = %s, want %s", resp.Digits, tt.wantDigits)
+			}
+			if status != http.StatusOK && resp.Error == "" {
+				t.Errorf("expected an error message")
+			}
+		})
+	}
+}
+
+func TestServer_Candidates(t *testing.T) {
+	ts := newTestServer(t)
+	var resp CandidatesResponse
+
+	// every word has one of t, a, r, e or s
+	req := CandidatesRequest{History: []Turn{{Guess: "tares", Pattern: "⬛⬛⬛⬛⬛"}}}
+	var e Error
+	if status := call(t, ts, "/candidates", req, &e); status != http.StatusUnprocessableEntity {
+		t.Errorf("status = %d, want %d", status, http.StatusUnprocessableEntity)
+	}
+
+	req = CandidatesRequest{History: []Turn{{Guess: "tares", Pattern: "gy"}}}
+	if status := call(t, ts, "/candidates", req, &e); status != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", status, http.StatusBadRequest)
+	}
+
+	req = CandidatesRequest{History: []Turn{{Guess: "light", Pattern: ".gggg"}}, Limit: 3}
+	if status := call(t, ts, "/candidates", req, &resp); status != http.StatusOK {
+		t.Fatalf("status = %d", status)
+	}
+	if resp.Count != 6 || len(resp.Candidates) != 3 {
+		t.Errorf("got count %d and %d candidates, want 6 and 3", resp.Count, len(resp.Candidates))
+	}
+}
+
+func TestServer_SuggestAndSolve(t *testing.T) {
+	ts := newTestServer(t)
+
+	var suggestion SuggestResponse
+	if status := call(t, ts, "/suggest", SuggestRequest{}, &suggestion); status != http.StatusOK {
+		t.Fatalf("status = %d", status)
+	}
+	if suggestion.Guess != "tares" || suggestion.Remaining != len(testWords) {
+		t.Errorf("got %+v, want the default opener over every word", suggestion)
+	}
+
+	// concurrent solves share the patterns
+	var wg sync.WaitGroup
+	for _, answer := range testWords {
+		wg.Add(1)
+		go func(answer string) {
+			defer wg.Done()
+			var resp SolveResponse
+			if status := call(t, ts, "/solve", SolveRequest{Answer: answer}, &resp); status != http.StatusOK {
+				t.Errorf("%s: status = %d", answer, status)
+				return
+			}
+			last := resp.Guesses[len(resp.Guesses)-1]
+			if resp.Won != (last.Guess == answer) || resp.Won != (last.Pattern == "ggggg") {
+				t.Errorf("%s: got %+v", answer, resp)
+			}
+		}(answer)
+	}
+	wg.Wait()
+}
+
+func TestServer_MethodNotAllowed(t *testing.T) {
+	ts := newTestServer(t)
+	r, err := http.Get(ts.URL + "/suggest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Body.Close()
+	if r.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("status = %d, want %d", r.StatusCode, http.StatusMethodNotAllowed)
+	}
+}
+
+// TestServer_SolveError checks a solve that goes wrong, here because the patterns were built
+// with another scoring, is a server error rather than the end of the process
+func TestServer_SolveError(t *testing.T) {
+	dict := primitives.Dictionary{}
+	for _, w := range testWords {
+		dict = append(dict, primitives.MakeWord(w))
+	}
+	ts := httptest.NewServer(New(cached.BuildPatterns(dict), primitives.Peaks, cached.Entropy{}))
+	t.Cleanup(ts.Close)
+
+	var resp Error
+	if status := call(t, ts, "/solve", SolveRequest{Answer: "light"}, &resp); status != http.StatusInternalServerError {
+		t.Errorf("status = %d, want %d (%s)", status, http.StatusInternalServerError, resp.Message)
+	}
+}
@@ -0,0 +1,263 @@
+// Package sessions keeps games in progress between requests: a session is created with an
+// answer, takes guesses one at a time, gives hints from the solver and is persisted so a
+// restart can resume it. Sessions that haven't been played for a while expire.
+package sessions
+
+import (
+	"bit-wordy/src/cached"
+	"bit-wordy/src/games"
+	"bit-wordy/src/primitives"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	mrand "math/rand"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrNotFound is returned for unknown or expired sessions
+	ErrNotFound = errors.New("no such session")
+	// ErrGameOver is returned when guessing in a game that is already won or lost
+	ErrGameOver = errors.New("the game is over")
+	// ErrNotInVocab is returned for answers and guesses the patterns don't know
+	ErrNotInVocab = errors.New("not in the vocabulary")
+)
+
+// DefaultTTL is how long a session lasts without being played
+const DefaultTTL = 7 * 24 * time.Hour
+
+// Session is the stored state of one game, the guesses are replayed against the answer to
+// rebuild it, so only the words need saving
+type Session struct {
+	ID      string    `json:"id"`
+	Answer  string    `json:"answer"`
+	Guesses []string  `json:"guesses"`
+	Created time.Time `json:"created"`
+	Updated time.Time `json:"updated"`
+}
+
+// State is a session with its game replayed and the answers still possible
+type State struct {
+	Session
+	Game      *games.Game
+	Remaining *cached.Patterns
+}
+
+// Over returns true if the game is won or lost
+func (s State) Over() bool {
+	return s.Game.IsWon() || s.Game.IsLost()
+}
+
+// Hint is the guess the solver would make next
+type Hint struct {
+	Guess   primitives.Word
+	Entropy float64
+}
+
+// Manager creates, plays and persists sessions. It is safe for concurrent use.
+type Manager struct {
+	// TTL is how long a session lasts without being played
+	TTL time.Duration
+	// Opener is the hint on the first turn
+	Opener   primitives.Word
+	patterns *cached.Patterns
+	scoring  primitives.Scoring
+	strategy cached.Strategy
+	store    FileStore
+	rng      *mrand.Rand
+	mu       sync.Mutex
+	sessions map[string]*Session
+}
+
+// NewManager loads the stored sessions, dropping those that have expired
+func NewManager(
+	store FileStore,
+	p *cached.Patterns,
+	scoring primitives.Scoring,
+	strategy cached.Strategy,
+) (*Manager, error) {
+	sessions, err := store.Load()
+	if err != nil {
+		return nil, fmt.Errorf("loading sessions from %s: %w", store.Path, err)
+	}
+	m := &Manager{
+		TTL:      DefaultTTL,
+		Opener:   cached.NewSolver(p).Opener,
+		patterns: p,
+		scoring:  scoring,
+		strategy: strategy,
+		store:    store,
+		rng:      mrand.New(mrand.NewSource(time.Now().UnixNano())),
+		sessions: sessions,
+	}
+
+	return m, nil
+}
+
+// newID returns a random hex session id
+func newID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Create starts a session, a random answer is chosen if answer is empty
+func (m *Manager) Create(answer string) (State, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	word := primitives.MakeWord(answer)
+	if answer == "" {
+		word = m.patterns.Vocab[m.rng.Intn(len(m.patterns.Vocab))]
+	} else if !m.patterns.Contains(word) {
+		return State{}, fmt.Errorf("answer %q: %w", answer, ErrNotInVocab)
+	}
+
+	id, err := newID()
+	if err != nil {
+		return State{}, err
+	}
+	now := time.Now()
+	s := &Session{ID: id, Answer: word.String(), Guesses: []string{}, Created: now, Updated: now}
+	m.sessions[id] = s
+	m.expire(now)
+	if err = m.store.Save(m.sessions); err != nil {
+		delete(m.sessions, id)
+		return State{}, err
+	}
+
+	return m.state(s)
+}
+
+// Get returns the state of a session
+func (m *Manager) Get(id string) (State, error) {
+	s, err := m.snapshot(id)
+	if err != nil {
+		return State{}, err
+	}
+	return m.state(&s)
+}
+
+// Guess plays a guess in the session
+func (m *Manager) Guess(id, guess string) (State, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s, err := m.get(id)
+	if err != nil {
+		return State{}, err
+	}
+	state, err := m.state(s)
+	if err != nil {
+		return State{}, err
+	}
+	if state.Over() {
+		return state, ErrGameOver
+	}
+	word := primitives.MakeWord(guess)
+	if !m.patterns.Contains(word) {
+		return state, fmt.Errorf("guess %q: %w", guess, ErrNotInVocab)
+	}
+
+	// the guess only counts once it is saved, until then the old session stays in place
+	next := state.Session
+	next.Guesses = append(next.Guesses, word.String())
+	next.Updated = time.Now()
+	m.sessions[id] = &next
+	if err = m.store.Save(m.sessions); err != nil {
+		m.sessions[id] = s
+		return State{}, err
+	}
+
+	return m.state(&next)
+}
+
+// Hint suggests the next guess of a session, the Opener on the first turn. The strategy
+// works on a copy of the session, so hints don't hold up other sessions.
+func (m *Manager) Hint(id string) (Hint, error) {
+	s, err := m.snapshot(id)
+	if err != nil {
+		return Hint{}, err
+	}
+	state, err := m.state(&s)
+	if err != nil {
+		return Hint{}, err
+	}
+	if state.Over() {
+		return Hint{}, ErrGameOver
+	}
+
+	if len(s.Guesses) == 0 {
+		entropy, _ := m.patterns.EntropyOf(m.Opener)
+		return Hint{Guess: m.Opener, Entropy: entropy}, nil
+	}
+	guess, entropy := cached.ChooseWithin(m.strategy, state.Remaining, games.MaxGuesses-len(s.Guesses))
+	return Hint{Guess: guess, Entropy: entropy}, nil
+}
+
+// Expire removes the sessions that haven't been played within the TTL, returning how many
+func (m *Manager) Expire() (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	n := m.expire(time.Now())
+	if n == 0 {
+		return 0, nil
+	}
+	return n, m.store.Save(m.sessions)
+}
+
+func (m *Manager) expire(now time.Time) int {
+	n := 0
+	for id, s := range m.sessions {
+		if now.Sub(s.Updated) > m.TTL {
+			delete(m.sessions, id)
+			n++
+		}
+	}
+	return n
+}
+
+// get looks up a live session, the lock must be held
+func (m *Manager) get(id string) (*Session, error) {
+	s, ok := m.sessions[id]
+	if !ok || time.Since(s.Updated) > m.TTL {
+		return nil, ErrNotFound
+	}
+	return s, nil
+}
+
+// snapshot copies a live session, so it can be replayed without holding the lock
+func (m *Manager) snapshot(id string) (Session, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s, err := m.get(id)
+	if err != nil {
+		return Session{}, err
+	}
+	session := *s
+	session.Guesses = append([]string{}, s.Guesses...)
+	return session, nil
+}
+
+// state replays the session's guesses, the state gets a copy of the session so it can be
+// read after the lock is released
+func (m *Manager) state(s *Session) (State, error) {
+	g := games.NewGameWith(primitives.MakeWord(s.Answer), m.scoring)
+	for _, guess := range s.Guesses {
+		g.Guess(primitives.MakeWord(guess))
+	}
+	remaining, err := m.patterns.PruneHistory(g.Results)
+	if err != nil {
+		return State{}, fmt.Errorf("session %s: %w", s.ID, err)
+	}
+
+	session := *s
+	session.Guesses = append([]string{}, s.Guesses...)
+	return State{Session: session, Game: g, Remaining: remaining}, nil
+}
@@ -0,0 +1,145 @@
+package sessions
+
+import (
+	"bit-wordy/src/cached"
+	"bit-wordy/src/primitives"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func testManager(t *testing.T, path string) *Manager {
+	t.Helper()
+	dict := primitives.Dictionary{}
+	for _, w := range []string{"tares", "light", "might", "night", "sight", "match", "batch", "crane"} {
+		dict = append(dict, primitives.MakeWord(w))
+	}
+	m, err := NewManager(FileStore{Path: path}, cached.BuildPatterns(dict), primitives.Classic, cached.Entropy{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestManager_ResumesAfterRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sessions.json")
+	m := testManager(t, path)
+
+	state, err := m.Create("light")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = m.Guess(state.ID, "tares"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = m.Guess(state.ID, "zzzzz"); !errors.Is(err, ErrNotInVocab) {
+		t.Errorf("guessing a word outside the vocabulary gave %v", err)
+	}
+
+	// a new manager over the same file carries on the game
+	m = testManager(t, path)
+	state, err = m.Get(state.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(state.Game.Results) != 1 || state.Game.Results[0].Word != primitives.MakeWord("tares") {
+		t.Fatalf("resumed with %v", state.Game.Results)
+	}
+	hint, err := m.Hint(state.ID)
+	if err != nil || !state.Remaining.Contains(hint.Guess) {
+		t.Errorf("hint %s, %v is not a remaining answer", hint.Guess, err)
+	}
+
+	if state, err = m.Guess(state.ID, "light"); err != nil || !state.Game.IsWon() {
+		t.Fatalf("winning guess gave %v, %v", state.Game, err)
+	}
+	if _, err = m.Guess(state.ID, "night"); !errors.Is(err, ErrGameOver) {
+		t.Errorf("guessing after winning gave %v", err)
+	}
+}
+
+func TestManager_Expire(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sessions.json")
+	m := testManager(t, path)
+	old, err := m.Create("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.sessions[old.ID].Updated = time.Now().Add(-2 * m.TTL)
+	fresh, err := m.Create("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = m.Get(old.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expired session gave %v", err)
+	}
+	m = testManager(t, path)
+	if _, err = m.Get(fresh.ID); err != nil {
+		t.Errorf("fresh session: %v", err)
+	}
+	if len(m.sessions) != 1 {
+		t.Errorf("%d sessions stored, want 1", len(m.sessions))
+	}
+}
+
+// TestManager_FailedSaveDoesNotCount checks a guess that can't be saved is not played
+func TestManager_FailedSaveDoesNotCount(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "store")
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	m := testManager(t, filepath.Join(dir, "sessions.json"))
+	state, err := m.Create("light")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// with the directory gone the store can't be written
+	if err = os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = m.Guess(state.ID, "tares"); err == nil {
+		t.Fatal("guess was saved without a store")
+	}
+	if state, err = m.Get(state.ID); err != nil || len(state.Guesses) != 0 {
+		t.Errorf("after the failed save the session has guesses %v, %v", state.Guesses, err)
+	}
+	if _, err = m.Create("night"); err == nil || len(m.sessions) != 1 {
+		t.Errorf("unsaved session was kept, %d sessions, %v", len(m.sessions), err)
+	}
+}
+
+// TestManager_ConcurrentHints plays and hints several sessions at once, for the race detector
+func TestManager_ConcurrentHints(t *testing.T) {
+	m := testManager(t, filepath.Join(t.TempDir(), "sessions.json"))
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		state, err := m.Create("")
+		if err != nil {
+			t.Fatal(err)
+		}
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			for {
+				hint, err := m.Hint(id)
+				if errors.Is(err, ErrGameOver) {
+					return
+				}
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if _, err = m.Guess(id, hint.Guess.String()); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}(state.ID)
+	}
+	wg.Wait()
+}
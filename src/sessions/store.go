@@ -0,0 +1,55 @@
+package sessions
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// FileStore persists sessions as a single json file. Every save rewrites a temporary file
+// and renames it over the old one, so a crash mid write leaves the previous sessions intact.
+type FileStore struct {
+	Path string
+}
+
+// Load reads every stored session, a missing file is an empty store
+func (f FileStore) Load() (map[string]*Session, error) {
+	sessions := map[string]*Session{}
+	content, err := ioutil.ReadFile(f.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return sessions, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(content, &sessions); err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+// Save replaces the stored sessions
+func (f FileStore) Save(sessions map[string]*Session) error {
+	content, err := json.MarshalIndent(sessions, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(f.Path), filepath.Base(f.Path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), f.Path)
+}
@@ -0,0 +1,95 @@
+package solver_test
+
+import (
+	"bit-wordy/src/cached"
+	"bit-wordy/src/games"
+	"bit-wordy/src/primitives"
+	"bit-wordy/src/solver"
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// entropyTolerance is how far apart the two solvers' entropies for a guess may be, they are
+// computed differently so may differ in the last few bits
+const entropyTolerance = 1e-9
+
+// randomDictionary makes n distinct words from the letters, always including tares so both
+// solvers can open with it. A small alphabet gives lots of shared letters and so long games.
+func randomDictionary(seed int64, letters string, n int) primitives.Dictionary {
+	rng := rand.New(rand.NewSource(seed))
+	seen := map[primitives.Word]bool{solver.DefaultOpener: true}
+	dict := primitives.Dictionary{solver.DefaultOpener}
+	for len(dict) < n {
+		w := primitives.Word{}
+		for i := range w {
+			w[i] = rune(letters[rng.Intn(len(letters))])
+		}
+		if !seen[w] {
+			seen[w] = true
+			dict = append(dict, w)
+		}
+	}
+	return dict
+}
+
+// TestSolversAgree plays the uncached Solver and the FastSolver over every answer of a
+// dictionary with the same opener, and checks they make the same guesses, get the same
+// patterns and agree on the entropy of each guess.
+func TestSolversAgree(t *testing.T) {
+	tests := []struct {
+		name    string
+		letters string
+		size    int
+	}{
+		{"sparse", "abcdefghilmnoprstuy", 600},
+		{"dense", "aeirst", 400},
+		{"tiny", "tares", 60},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dict := randomDictionary(1, tt.letters, tt.size)
+			fast := cached.NewSolver(cached.BuildPatterns(dict))
+			if err := fast.SetOpener(solver.DefaultOpener); err != nil {
+				t.Fatal(err)
+			}
+
+			for _, answer := range dict {
+				slow := solver.NewSolver(games.NewGame(answer), dict)
+				slowGame := slow.Solve()
+				fast.Reset()
+				fastGame, _, err := fast.Solve(games.NewGame(answer))
+				if err != nil {
+					t.Fatalf("answer %s: %v", answer, err)
+				}
+
+				if diverged, why := divergence(slowGame, slow.Scores, fastGame, fast.Trace()); diverged {
+					t.Errorf(
+						"answer %s: %s\n--SOLVER--\n%s\nentropies: %v\n--FAST SOLVER--\n%s\n%s",
+						answer, why, slowGame, slow.Scores, fastGame, fast,
+					)
+				}
+			}
+		})
+	}
+}
+
+// divergence finds the first difference between the two games
+func divergence(slow *games.Game, scores []float64, fast *games.Game, trace []cached.GuessOutcome) (bool, string) {
+	for turn := 0; turn < len(slow.Results) || turn < len(fast.Results); turn++ {
+		if turn >= len(slow.Results) || turn >= len(fast.Results) {
+			return true, "the games are different lengths"
+		}
+		if slow.Results[turn] != fast.Results[turn] {
+			return true, fmt.Sprintf("the guesses differ on turn %d", turn+1)
+		}
+		if math.Abs(scores[turn]-trace[turn].ExpectedInfo) > entropyTolerance {
+			return true, fmt.Sprintf("the entropies differ on turn %d", turn+1)
+		}
+	}
+	if slow.IsWon() != fast.IsWon() {
+		return true, "only one of the games was won"
+	}
+	return false, ""
+}
@@ -7,14 +7,19 @@ import (
 	"fmt"
 	"log"
 	"math"
-	"sort"
 )
 
+// DefaultOpener is the first guess, unless the Solver's Opener is changed
+var DefaultOpener = primitives.Word{'t', 'a', 'r', 'e', 's'}
+
 // Solver is a struct that encapsulates the solving algorithm
 type Solver struct {
 	possibleAnswers primitives.Dictionary
 	Game            *games.Game
 	Result          primitives.Word
+	Opener          primitives.Word
+	// Scores is the entropy of each guess when it was chosen, against the answers remaining
+	Scores []float64
 }
 
 // NewSolver returns a reference to a new solver instance
@@ -22,6 +27,7 @@ func NewSolver(game *games.Game, dict primitives.Dictionary) *Solver {
 	return &Solver{
 		possibleAnswers: dict,
 		Game:            game,
+		Opener:          DefaultOpener,
 	}
 }
 
@@ -34,17 +40,15 @@ func (s Solver) GameScore() int {
 	return len(s.Game.Results)
 }
 
-// guessScore exists to simplify guess ranking logic
-type guessScore struct {
-	guess primitives.Word
-	score float64
-}
+// tieTolerance is how close two entropies must be to count as a tie
+const tieTolerance = 1e-9
 
 func (s *Solver) Solve() *games.Game {
 	var remainingAnswers primitives.Dictionary
 
 	// initialise the first guess (it's always the same)
-	guess := primitives.Word{'t', 'a', 'r', 'e', 's'}
+	guess := s.Opener
+	s.Scores = []float64{Entropy(guess, s.possibleAnswers)}
 
 	for {
 		// get pattern from guess
@@ -59,20 +63,21 @@ func (s *Solver) Solve() *games.Game {
 		// refine possible answers based on pattern
 		remainingAnswers = primitives.Dictionary{}
 		for _, word := range s.possibleAnswers {
-			pattern := guess.CheckGuess(word)
+			pattern := word.CheckGuess(guess)
 			if pattern == lastPattern && word != guess {
 				remainingAnswers = append(remainingAnswers, word)
 			}
 		}
 
-		if len(remainingAnswers) == 1 {
+		if s.Game.IsLost() {
+			// We ran out of guesses.
+			break
+		} else if len(remainingAnswers) == 1 {
 			// There's only one choice, we win!
 			s.Result = remainingAnswers[0]
+			s.Scores = append(s.Scores, 0)
 			s.Game.Guess(s.Result)
 			break
-		} else if s.Game.IsLost() {
-			// We ran out of guesses.
-			break
 		} else if len(remainingAnswers) <= 0 {
 			// We eliminated all possible answers. This implies some of our guesses
 			// were not possible words given the game state at the point they were
@@ -82,20 +87,17 @@ func (s *Solver) Solve() *games.Game {
 
 		s.possibleAnswers = remainingAnswers
 
-		// go through the guesses and compose them with a score and store in scoredGuesses
-		scoredGuesses := make([]guessScore, len(s.possibleAnswers))
-		for i, word := range s.possibleAnswers {
-			scoredGuesses[i] = guessScore{word, Entropy(word, s.possibleAnswers)}
-		}
-
-		// order the guess scoredGuesses by descending score order i.e. best is scoredGuesses[0]
-		descendingScore := func(i, j int) bool {
-			return scoredGuesses[i].score > scoredGuesses[j].score
+		// score each remaining answer as a guess and keep the best, ties going to the
+		// earliest in the dictionary so that the game is the same every time
+		topScore := -1.0
+		for _, word := range s.possibleAnswers {
+			if score := Entropy(word, s.possibleAnswers); score > topScore+tieTolerance {
+				guess, topScore = word, score
+			}
 		}
-		sort.Slice(scoredGuesses, descendingScore)
 
 		// update the guesses
-		guess = scoredGuesses[0].guess
+		s.Scores = append(s.Scores, topScore)
 	}
 
 	return s.Game
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"bit-wordy/src/cached"
+	"bit-wordy/src/games"
+	"bit-wordy/src/primitives"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Tournament is the subcommand that plays several strategies over the same answers, each in
+// its own goroutine, and compares them: the distribution of guesses, the mean guesses of the
+// games won, the average over all games, the failure rate and the time taken by each, then
+// for every pair how many answers each solved in fewer guesses and whether the difference in
+// their averages is significant. Lost games score MaxGuesses+1. The answers are every word,
+// or --games chosen by --answer. Without any strategies named every one plays, the weighted
+// strategy only with --frequencies. The other entrants treat every answer as equally likely,
+// so the weighted strategy is the prior played against the uniform. Every entrant plays
+// normal rules, so --hard is rejected.
+type Tournament struct {
+	Strategies []string `arg:"positional"`
+	Games      int      `arg:"--games"`
+}
+
+// entrant is a strategy's results, indexed like the answers
+type entrant struct {
+	name    string
+	scores  []int
+	elapsed time.Duration
+}
+
+// Run is the implementation of Tournament
+func (t Tournament) Run(p *cached.Patterns) (err error) {
+	if p == nil {
+		if p, err = loadPatterns(); err != nil {
+			return err
+		}
+	}
+	if args.Hard {
+		// only the trap strategy has a hard mode, the others would play by different rules
+		return fmt.Errorf("--hard is not supported by the tournament, every entrant plays normal rules")
+	}
+	names := []string{}
+	for _, s := range t.Strategies {
+		names = append(names, strings.Split(s, ",")...)
+	}
+	if len(names) == 0 {
+		for _, s := range strategies {
+			if !s.needsPrior || prior != nil {
+				names = append(names, s.name)
+			}
+		}
+	}
+	// only the weighted entrant plays by the prior
+	p = p.Reweighted(nil)
+	solvers := make([]*cached.FastSolver, len(names))
+	for i, name := range names {
+		if solvers[i], err = newSolver(p); err != nil {
+			return err
+		}
+		if solvers[i].Strategy, err = parseStrategy(name); err != nil {
+			return err
+		}
+	}
+	answers, err := Bench{Games: t.Games}.answers()
+	if err != nil {
+		return err
+	}
+
+	// the patterns are only read, so the solvers can share them
+	entrants, errs := make([]entrant, len(solvers)), make([]error, len(solvers))
+	var wg sync.WaitGroup
+	for i, solver := range solvers {
+		wg.Add(1)
+		go func(i int, solver *cached.FastSolver) {
+			defer wg.Done()
+			entrants[i], errs[i] = play(solver, answers)
+		}(i, solver)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("%d games, scoring: %s, opener: %s\n\n", len(answers), scoring.Name, solvers[0].Opener)
+	fmt.Printf("%-10s", "strategy")
+	for n := 1; n <= games.MaxGuesses; n++ {
+		fmt.Printf("  %5d", n)
+	}
+	fmt.Printf("  %5s  %8s  %7s  %6s  %10s\n", "X", "mean won", "average", "failed", "per game")
+	for _, e := range entrants {
+		fmt.Println(e)
+	}
+
+	fmt.Printf("\n%-21s  %5s  %5s  %5s  %9s  %8s\n", "head to head", "wins", "ties", "loses", "Δ average", "p")
+	for i := range entrants {
+		for j := i + 1; j < len(entrants); j++ {
+			a, b := entrants[i], entrants[j]
+			wins, ties, losses := 0, 0, 0
+			diffs := make([]float64, len(answers))
+			for k := range answers {
+				switch {
+				case a.scores[k] < b.scores[k]:
+					wins++
+				case a.scores[k] > b.scores[k]:
+					losses++
+				default:
+					ties++
+				}
+				diffs[k] = float64(a.scores[k] - b.scores[k])
+			}
+			delta, p := pairedTTest(diffs)
+			fmt.Printf(
+				"%-21s  %5d  %5d  %5d  %+9.4f  %8.2g%s\n",
+				a.name+" v "+b.name, wins, ties, losses, delta, p, significance(p),
+			)
+		}
+	}
+	fmt.Println("\n* p < 0.05, ** p < 0.01, from a paired t-test of the scores per answer")
+	return nil
+}
+
+// play solves every answer, scoring lost games as MaxGuesses+1
+func play(solver *cached.FastSolver, answers primitives.Dictionary) (entrant, error) {
+	e := entrant{name: solver.Strategy.Name(), scores: make([]int, len(answers))}
+	start := time.Now()
+	for i, answer := range answers {
+		solver.Reset()
+		g, _, err := solver.Solve(newGame(answer))
+		if err != nil {
+			return e, fmt.Errorf("%s solving %s: %w", e.name, answer, err)
+		}
+		e.scores[i] = len(g.Results)
+		if g.IsLost() {
+			e.scores[i] = games.MaxGuesses + 1
+		}
+	}
+	e.elapsed = time.Since(start)
+	return e, nil
+}
+
+func (e entrant) String() string {
+	distribution := make([]int, games.MaxGuesses+2)
+	won, total := 0, 0
+	for _, score := range e.scores {
+		distribution[score]++
+		total += score
+		if score <= games.MaxGuesses {
+			won += score
+		}
+	}
+	lost := distribution[games.MaxGuesses+1]
+	s := fmt.Sprintf("%-10s", e.name)
+	for n := 1; n <= games.MaxGuesses; n++ {
+		s += fmt.Sprintf("  %5d", distribution[n])
+	}
+	mean := math.NaN()
+	if lost < len(e.scores) {
+		mean = float64(won) / float64(len(e.scores)-lost)
+	}
+	return s + fmt.Sprintf(
+		"  %5d  %8.4f  %7.4f  %5.2f%%  %10s",
+		lost, mean, float64(total)/float64(len(e.scores)),
+		100*float64(lost)/float64(len(e.scores)), e.elapsed/time.Duration(len(e.scores)),
+	)
+}
+
+func significance(p float64) string {
+	switch {
+	case p < 0.01:
+		return " **"
+	case p < 0.05:
+		return " *"
+	default:
+		return ""
+	}
+}
+
+// pairedTTest returns the mean of the differences and the two sided p-value of it being zero
+func pairedTTest(diffs []float64) (mean, p float64) {
+	n := float64(len(diffs))
+	for _, d := range diffs {
+		mean += d
+	}
+	mean /= n
+	variance := 0.0
+	for _, d := range diffs {
+		variance += (d - mean) * (d - mean)
+	}
+	if n < 2 || variance == 0 {
+		if mean == 0 {
+			return 0, 1
+		}
+		return mean, 0
+	}
+	variance /= n - 1
+
+	t := mean / math.Sqrt(variance/n)
+	df := n - 1
+	return mean, incompleteBeta(df/2, 0.5, df/(df+t*t))
+}
+
+// incompleteBeta is the regularised incomplete beta function I_x(a, b), by its continued
+// fraction (Numerical Recipes, 6.4)
+func incompleteBeta(a, b, x float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	if x >= 1 {
+		return 1
+	}
+	la, _ := math.Lgamma(a)
+	lb, _ := math.Lgamma(b)
+	lab, _ := math.Lgamma(a + b)
+	front := math.Exp(lab - la - lb + a*math.Log(x) + b*math.Log(1-x))
+	if x > (a+1)/(a+b+2) {
+		return 1 - front*betaFraction(b, a, 1-x)/b
+	}
+	return front * betaFraction(a, b, x) / a
+}
+
+// betaFraction evaluates the continued fraction of incompleteBeta by Lentz's method
+func betaFraction(a, b, x float64) float64 {
+	const (
+		iterations = 300
+		epsilon    = 1e-14
+		tiny       = 1e-300
+	)
+	c, d := 1.0, 1-(a+b)*x/(a+1)
+	if math.Abs(d) < tiny {
+		d = tiny
+	}
+	d = 1 / d
+	h := d
+	for m := 1.0; m <= iterations; m++ {
+		// the even then the odd step of the fraction
+		for _, num := range []float64{
+			m * (b - m) * x / ((a + 2*m - 1) * (a + 2*m)),
+			-(a + m) * (a + b + m) * x / ((a + 2*m) * (a + 2*m + 1)),
+		} {
+			d = 1 + num*d
+			if math.Abs(d) < tiny {
+				d = tiny
+			}
+			c = 1 + num/c
+			if math.Abs(c) < tiny {
+				c = tiny
+			}
+			d = 1 / d
+			h *= d * c
+		}
+		if math.Abs(d*c-1) < epsilon {
+			break
+		}
+	}
+	return h
+}
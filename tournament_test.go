@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bit-wordy/src/cached"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTournament_Run(t *testing.T) {
+	dict := []string{"tares", "light", "might", "night", "sight", "crane", "batch", "match", "pious", "zesty"}
+	freqs := filepath.Join(t.TempDir(), "freqs")
+	if err := os.WriteFile(freqs, []byte("light 1000\nmight 10\ncrane 500\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		cmdline []string
+		want    []string
+	}{
+		{[]string{"tournament"}, []string{"entropy", "minimax", "expected", "trap", "lookahead"}},
+		{[]string{"--frequencies", freqs, "tournament"}, []string{"entropy", "minimax", "expected", "trap", "lookahead", "weighted"}},
+		{[]string{"--frequencies", freqs, "tournament", "weighted,entropy"}, []string{"weighted", "entropy"}},
+	}
+	for _, tt := range tests {
+		parseArgs(t, append([]string{"--words", wordsFile(t, dict...)}, tt.cmdline...)...)
+		p := cached.BuildPatternsWith(words, scoring)
+		out := captureStdout(t, func() error { return args.Tournament.Run(p) })
+
+		// the entrants are listed after the header, up to the blank line before the head to head
+		lines := strings.Split(out, "\n")
+		entrants := []string{}
+		for _, line := range lines[3:] {
+			if line == "" {
+				break
+			}
+			entrants = append(entrants, strings.Fields(line)[0])
+		}
+		if strings.Join(entrants, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("%v played %v, want %v", tt.cmdline, entrants, tt.want)
+		}
+		if !strings.HasPrefix(out, "10 games") {
+			t.Errorf("%v printed:\n%s", tt.cmdline, out)
+		}
+	}
+
+	parseArgs(t, "--words", wordsFile(t, dict...), "tournament", "weighted")
+	if err := args.Tournament.Run(cached.BuildPatternsWith(words, scoring)); err == nil {
+		t.Errorf("the weighted strategy played without --frequencies")
+	}
+}
+
+func TestPairedTTest(t *testing.T) {
+	// the two sided 5% critical values of Student's t distribution
+	tests := []struct {
+		df       int
+		critical float64
+	}{
+		{1, 12.7062},
+		{10, 2.2281},
+		{30, 2.0423},
+		{1000, 1.9623},
+	}
+	for _, tt := range tests {
+		// differences with a sample standard deviation of one and a mean giving the critical t
+		n := tt.df + 1
+		diffs := make([]float64, n)
+		sd := math.Sqrt(float64(n) * float64(n+1) / 12)
+		for i := range diffs {
+			diffs[i] = (float64(i)-float64(n-1)/2)/sd + tt.critical/math.Sqrt(float64(n))
+		}
+		mean, p := pairedTTest(diffs)
+		if want := tt.critical / math.Sqrt(float64(n)); math.Abs(mean-want) > 1e-9 {
+			t.Errorf("df %d: mean %f, want %f", tt.df, mean, want)
+		}
+		if math.Abs(p-0.05) > 1e-4 {
+			t.Errorf("df %d: p is %f at t = %.4f, want 0.05", tt.df, p, tt.critical)
+		}
+		df := float64(tt.df)
+		if p := incompleteBeta(df/2, 0.5, df/(df+tt.critical*tt.critical)); math.Abs(p-0.05) > 1e-4 {
+			t.Errorf("df %d: I_x(%.1f, 0.5) is %f, want 0.05", tt.df, df/2, p)
+		}
+	}
+}
+
+func TestIncompleteBeta(t *testing.T) {
+	// closed forms: I_x(a, 1) = x^a and I_x(1, b) = 1 - (1-x)^b
+	for _, x := range []float64{0, 0.1, 0.5, 0.9, 1} {
+		for _, a := range []float64{0.5, 1, 2, 7.5} {
+			if got, want := incompleteBeta(a, 1, x), math.Pow(x, a); math.Abs(got-want) > 1e-10 {
+				t.Errorf("I_%.1f(%.1f, 1) = %f, want %f", x, a, got, want)
+			}
+			if got, want := incompleteBeta(1, a, x), 1-math.Pow(1-x, a); math.Abs(got-want) > 1e-10 {
+				t.Errorf("I_%.1f(1, %.1f) = %f, want %f", x, a, got, want)
+			}
+		}
+	}
+}
+
+func TestPairedTTest_Degenerate(t *testing.T) {
+	if mean, p := pairedTTest([]float64{0, 0, 0}); mean != 0 || p != 1 {
+		t.Errorf("no differences gave mean %f and p %f", mean, p)
+	}
+	if mean, p := pairedTTest([]float64{1, 1, 1}); mean != 1 || p != 0 {
+		t.Errorf("constant differences gave mean %f and p %f", mean, p)
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"bit-wordy/src/cached"
+	"fmt"
+	"math/rand"
+	"runtime"
+	"time"
+)
+
+// Verify is the subcommand that checks the loaded cache against fresh scoring, either every
+// entry or a --sample of them, so a cache copied from another machine can be trusted. It
+// lists the first --max mismatches and fails if there are any.
+type Verify struct {
+	Sample  int `arg:"--sample"`
+	Workers int `arg:"--workers"`
+	Max     int `arg:"--max" default:"20"`
+}
+
+// Run is the implementation of Verify
+func (v Verify) Run(p *cached.Patterns) (err error) {
+	if p == nil {
+		if p, err = loadPatterns(); err != nil {
+			return err
+		}
+	}
+	workers := v.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	start := time.Now()
+	result, err := p.Verify(scoring, v.Sample, rand.New(rand.NewSource(seed())), workers)
+	if err != nil {
+		return err
+	}
+	for i, m := range result.Mismatches {
+		if i == v.Max {
+			fmt.Printf("... and %d more\n", len(result.Mismatches)-v.Max)
+			break
+		}
+		fmt.Println(m)
+	}
+	fmt.Printf(
+		"checked %d entries of %s with %s scoring in %s, %d mismatches\n",
+		result.Checked, cache, scoring.Name, time.Since(start).Round(time.Millisecond), len(result.Mismatches),
+	)
+
+	if len(result.Mismatches) > 0 {
+		return fmt.Errorf("the cache %s doesn't match fresh scoring", cache)
+	}
+	return nil
+}